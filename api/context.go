@@ -7,6 +7,7 @@ import (
     "net/http"
     "sync"
     
+    "github.com/Corphon/daoframe/core"
     "github.com/Corphon/daoframe/tools/cache"
 )
 
@@ -36,23 +37,41 @@ type Context struct {
     // 性能追踪
     tracer       *Tracer
     spans        []*Span
+
+    // 上游传入的道家上下文
+    dao          *core.DaoContext
+    release      context.CancelFunc
 }
 
 // NewContext 创建新的上下文
+// 请求头中的道家上下文被还原，其截止时间约束本上下文，处理结束后须调用 Release
 func NewContext(w http.ResponseWriter, r *http.Request, opts ...ContextOption) *Context {
+    dao, release, err := core.ExtractHTTP(r)
+    if err != nil {
+        // 无法解析的上游字段不影响请求处理
+        dao, release = core.NewDaoContext(r.Context()), func() {}
+    }
+
     ctx := &Context{
-        Context:        r.Context(),
+        Context:        dao,
         Request:        r,
         ResponseWriter: w,
         Params:        make(map[string]string),
         startTime:     time.Now(),
         cache:         cache.New(cache.DefaultExpiration),
+        dao:           dao,
+        release:       release,
     }
     
     // 应用选项
     for _, opt := range opts {
         opt(ctx)
     }
+
+    // 未指定请求标识时沿用追踪标识
+    if ctx.requestID == "" {
+        ctx.requestID = core.EnsureTraceID(dao)
+    }
     
     // 初始化追踪
     ctx.tracer = NewTracer(ctx.requestID)
@@ -61,6 +80,18 @@ func NewContext(w http.ResponseWriter, r *http.Request, opts ...ContextOption) *
     return ctx
 }
 
+// Dao 获取道家上下文，调用下游服务时可通过 core.InjectHTTP 继续传播
+func (c *Context) Dao() *core.DaoContext {
+    return c.dao
+}
+
+// Release 释放上下文持有的资源
+func (c *Context) Release() {
+    if c.release != nil {
+        c.release()
+    }
+}
+
 // JSON 返回JSON响应
 func (c *Context) JSON(code int, data interface{}) error {
     if c.wrote {
@@ -73,6 +73,7 @@ func (r *Router) Handle(w http.ResponseWriter, req *http.Request) {
     route, params, err := r.findRoute(req.Method, req.URL.Path)
     if err != nil {
         ctx.Error(err)
+        ctx.Release()
         return
     }
     
@@ -83,11 +84,13 @@ func (r *Router) Handle(w http.ResponseWriter, req *http.Request) {
     // 限流检查
     if err := r.limiter.Allow(ctx); err != nil {
         ctx.Error(err)
+        ctx.Release()
         return
     }
     
     // 提交到工作池处理
     r.pool.Submit(func() {
+        defer ctx.Release()
         defer r.recoverPanic(ctx)
         
         // 执行请求
@@ -5,8 +5,10 @@ package core
 import (
     "context"
     "errors"
+    "fmt"
     "sync"
     "time"
+    "github.com/Corphon/daoframe/core/clock"
     "github.com/Corphon/daoframe/core/state"  // 新的导入
 )
 
@@ -29,51 +31,107 @@ const (
 type AdaptSystem struct {
     mu          sync.RWMutex
     stateManager *state.StateManager  // 使用状态管理器
-    handlers    map[string]AdaptHandler
+    handlers    map[string]*handlerEntry
     mode        AdaptMode
-    active      bool
     interval    time.Duration
     lastAdapt   time.Time
-    yinHandler  []AdaptHandler
-    yangHandler []AdaptHandler
+    yinHandler  []string // 阴性处理器名称
+    yangHandler []string // 阳性处理器名称
+    onError     func(name string, err error)
+    clock       clock.Clock    // 时钟
+    schedule    clock.Schedule // 昼夜时刻表
     // 新增字段
     adaptiveThreshold float64
     environmentState  map[string]int
     adaptHistory     []AdaptiveAction
     balanceFactors   map[string]float64
-    currentState state.State  // 新增字段用于跟踪系统状态
+
+    // 闭环控制
+    controller  *adaptController // 统计效果与稳定性
+    autoMode    bool             // 是否由控制器切换模式
+    metrics     AdaptiveMetrics  // 最近一个周期的指标
+    historySize int              // adaptHistory 的容量
+
+    // 运行控制
+    stopCh  chan struct{}   // 关闭后不再开始新的适应周期
+    running sync.WaitGroup  // 运行中的适应循环
 }
 
 // NewAdaptSystem 创建新的自适应系统
 func NewAdaptSystem(interval time.Duration) *AdaptSystem {
     return &AdaptSystem{
-        handlers:    make(map[string]AdaptHandler),
+        handlers:    make(map[string]*handlerEntry),
         mode:       NaturalAdapt,
         interval:   interval,
-        yinHandler: make([]AdaptHandler, 0),
-        yangHandler: make([]AdaptHandler, 0),
-        currentState: state.StateInactive,  // 初始化状态
+        yinHandler: make([]string, 0),
+        yangHandler: make([]string, 0),
+        stateManager: state.NewStateManager(state.BaseMachine(),
+            state.WithInitialState(state.StateInactive)),
+        controller:        newAdaptController(DefaultAdaptControllerConfig()),
+        adaptiveThreshold: DefaultAdaptControllerConfig().EffectivenessThreshold,
+        adaptHistory:      make([]AdaptiveAction, 0),
+        historySize:       DefaultAdaptHistorySize,
+        clock:             clock.Real(),
+        schedule:          clock.DefaultSchedule(),
     }
 }
 
-// RegisterHandler 注册处理器
-func (as *AdaptSystem) RegisterHandler(name string, handler AdaptHandler, nature DaoPhase) error {
+// SetClock 设置时钟，应在 Start 之前调用
+func (as *AdaptSystem) SetClock(c clock.Clock) {
+    if c == nil {
+        return
+    }
+    as.mu.Lock()
+    defer as.mu.Unlock()
+    as.clock = c
+}
+
+// SetSchedule 设置昼夜时刻表
+func (as *AdaptSystem) SetSchedule(s clock.Schedule) {
+    as.mu.Lock()
+    defer as.mu.Unlock()
+    as.schedule = s
+}
+
+// clockOf 获取当前使用的时钟
+func (as *AdaptSystem) clockOf() clock.Clock {
+    as.mu.RLock()
+    defer as.mu.RUnlock()
+    return as.clock
+}
+
+// RegisterHandler 注册处理器，同名处理器将被替换
+func (as *AdaptSystem) RegisterHandler(name string, handler AdaptHandler, nature DaoPhase, opts ...HandlerOption) error {
     if handler == nil {
         return errors.New("handler cannot be nil")
     }
 
+    entry := &handlerEntry{
+        name:    name,
+        handler: handler,
+        nature:  nature,
+        policy:  ContinueOnError,
+    }
+    for _, opt := range opts {
+        opt(entry)
+    }
+
     as.mu.Lock()
     defer as.mu.Unlock()
 
-    as.handlers[name] = handler
+    if _, exists := as.handlers[name]; exists {
+        as.yinHandler = removeName(as.yinHandler, name)
+        as.yangHandler = removeName(as.yangHandler, name)
+    }
+    as.handlers[name] = entry
     
     // 根据性质分类处理器
     switch nature {
     case PhaseYinYang:
         if as.isYinDominant() {
-            as.yinHandler = append(as.yinHandler, handler)
+            as.yinHandler = append(as.yinHandler, name)
         } else {
-            as.yangHandler = append(as.yangHandler, handler)
+            as.yangHandler = append(as.yangHandler, name)
         }
     default:
         // 其他阶段的处理器保持中性
@@ -83,52 +141,137 @@ func (as *AdaptSystem) RegisterHandler(name string, handler AdaptHandler, nature
 }
 
 // SetMode 设置适应模式
+// 自动模式下，手动设置的模式至少保持 MinDwell 个周期
 func (as *AdaptSystem) SetMode(mode AdaptMode) {
     as.mu.Lock()
     defer as.mu.Unlock()
     as.mode = mode
+    as.controller.dwell = 0
 }
 
-// Start 启动自适应系统
-func (as *AdaptSystem) Start(ctx context.Context) error {
+// Mode 获取当前适应模式
+func (as *AdaptSystem) Mode() AdaptMode {
+    as.mu.RLock()
+    defer as.mu.RUnlock()
+    return as.mode
+}
+
+// EnableAutoMode 启用闭环控制，根据效果与稳定性趋势自动切换适应模式
+func (as *AdaptSystem) EnableAutoMode(config AdaptControllerConfig) {
     as.mu.Lock()
     defer as.mu.Unlock()
+    as.controller = newAdaptController(config)
+    as.autoMode = true
+    if config.EffectivenessThreshold > 0 {
+        as.adaptiveThreshold = config.EffectivenessThreshold
+    }
+}
 
-    if err := as.stateManager.TransitTo(state.StateActive); err != nil {
-        return fmt.Errorf("failed to start adapt system: %w", err)
+// DisableAutoMode 停用闭环控制，保持当前模式
+func (as *AdaptSystem) DisableAutoMode() {
+    as.mu.Lock()
+    defer as.mu.Unlock()
+    as.autoMode = false
+}
+
+// SetAdaptiveThreshold 设置效果阈值 (0-1)
+func (as *AdaptSystem) SetAdaptiveThreshold(threshold float64) {
+    as.mu.Lock()
+    defer as.mu.Unlock()
+    as.adaptiveThreshold = clampUnit(threshold)
+}
+
+// SetHistorySize 设置保留的适应动作数量
+func (as *AdaptSystem) SetHistorySize(size int) {
+    if size <= 0 {
+        return
     }
-    if as.currentState == state.StateActive {
+    as.mu.Lock()
+    defer as.mu.Unlock()
+    as.historySize = size
+    as.trimHistory()
+}
+
+// Metrics 获取最近一个周期的适应指标
+func (as *AdaptSystem) Metrics() AdaptiveMetrics {
+    as.mu.RLock()
+    defer as.mu.RUnlock()
+    return as.metrics
+}
+
+// History 获取适应动作历史，按时间排列
+func (as *AdaptSystem) History() []AdaptiveAction {
+    as.mu.RLock()
+    defer as.mu.RUnlock()
+    return append([]AdaptiveAction(nil), as.adaptHistory...)
+}
+
+// Start 启动自适应系统
+func (as *AdaptSystem) Start(ctx context.Context) error {
+    as.mu.Lock()
+    if as.stateManager.Is(state.StateActive) {
         as.mu.Unlock()
         return errors.New("adapt system is already running")
     }
-    
-    if !state.ValidateTransition(as.currentState, state.StateActive) {
+
+    if err := as.stateManager.TransitTo(state.StateActive); err != nil {
         as.mu.Unlock()
-        return errors.New("invalid state transition")
+        return fmt.Errorf("failed to start adapt system: %w", err)
     }
-    
-    as.active = true
-    as.currentState = state.StateActive
-    as.lastAdapt = time.Now()
+
+    as.lastAdapt = as.clock.Now()
+    as.stopCh = make(chan struct{})
+    as.running.Add(1)
+    stopCh := as.stopCh
     as.mu.Unlock()
 
-    go as.run(ctx)
+    go as.run(ctx, stopCh)
     return nil
 }
-// Stop 停止自适应系统
+
+// Stop 停止自适应系统，不再开始新的适应周期
+// 正在执行的处理器不会被打断，可通过 Drain 等待其完成
 func (as *AdaptSystem) Stop() {
     as.mu.Lock()
     defer as.mu.Unlock()
-    
-    if state.ValidateTransition(as.currentState, state.StateInactive) {
-        as.active = false
-        as.currentState = state.StateInactive
+
+    if as.stateManager.Can(state.StateInactive) {
+        _ = as.stateManager.TransitTo(state.StateInactive)
+    }
+    if as.stopCh != nil {
+        close(as.stopCh)
+        as.stopCh = nil
     }
 }
 
+// Drain 等待正在执行的适应周期结束
+func (as *AdaptSystem) Drain(ctx context.Context) error {
+    drained := make(chan struct{})
+    go func() {
+        as.running.Wait()
+        close(drained)
+    }()
+
+    select {
+    case <-drained:
+        return nil
+    case <-ctx.Done():
+        return ctx.Err()
+    }
+}
+
+// State 获取自适应系统当前状态
+func (as *AdaptSystem) State() state.State {
+    return as.stateManager.Current()
+}
+
 // run 运行自适应循环
-func (as *AdaptSystem) run(ctx context.Context) {
-    ticker := time.NewTicker(as.interval)
+func (as *AdaptSystem) run(ctx context.Context, stopCh <-chan struct{}) {
+    defer as.running.Done()
+
+    as.mu.RLock()
+    ticker := as.clock.NewTicker(as.interval)
+    as.mu.RUnlock()
     defer ticker.Stop()
 
     for {
@@ -136,7 +279,9 @@ func (as *AdaptSystem) run(ctx context.Context) {
         case <-ctx.Done():
             as.Stop()
             return
-        case <-ticker.C:
+        case <-stopCh:
+            return
+        case <-ticker.C():
             if err := as.adapt(ctx); err != nil {
                 continue
             }
@@ -148,84 +293,156 @@ func (as *AdaptSystem) run(ctx context.Context) {
 func (as *AdaptSystem) adapt(ctx context.Context) error {
     as.mu.RLock()
     mode := as.mode
-    handlers := make([]struct {
-        name    string
-        handler AdaptHandler
-    }, 0, len(as.handlers))
-
-    for name, handler := range as.handlers {
-        handlers = append(handlers, struct {
-            name    string
-            handler AdaptHandler
-        }{name, handler})
-    }
+    handlers := as.dueHandlers(as.clock.Now())
     as.mu.RUnlock()
 
     daoCtx := NewDaoContext(ctx)
 
+    var results []handlerResult
     switch mode {
     case NaturalAdapt:
-        return as.naturalAdapt(daoCtx, handlers)
+        results = as.naturalAdapt(daoCtx, handlers)
     case ActiveAdapt:
-        return as.activeAdapt(daoCtx, handlers)
+        results = as.activeAdapt(daoCtx, handlers)
     default:
-        return as.passiveAdapt(daoCtx, handlers)
+        results = as.passiveAdapt(daoCtx, handlers)
     }
+
+    return as.feedback(mode, results)
+}
+
+// feedback 更新处理器统计、记录适应动作并由控制器调整模式
+// 返回本周期全部处理器错误
+func (as *AdaptSystem) feedback(mode AdaptMode, results []handlerResult) error {
+    as.mu.Lock()
+
+    as.lastAdapt = as.clock.Now()
+    var errs []error
+    for _, r := range results {
+        stats := &r.entry.stats
+        if r.skipped {
+            stats.Skipped++
+            continue
+        }
+        stats.Runs++
+        stats.Retries += uint64(r.retries)
+        stats.Timeouts += uint64(r.timeouts)
+        stats.LastRun = r.at
+        stats.LastDuration = r.duration
+        stats.TotalDuration += r.duration
+        stats.LastError = r.err
+        if r.err != nil {
+            stats.Failures++
+            if r.entry.policy == DisableOnError {
+                stats.Disabled = true
+            }
+            errs = append(errs, fmt.Errorf("adapt handler %s: %w", r.name, r.err))
+        }
+        as.adaptHistory = append(as.adaptHistory, r.action(mode))
+    }
+
+    as.metrics = as.controller.observe(results)
+    if as.autoMode {
+        next := as.controller.decide(as.mode, as.adaptiveThreshold, as.metrics)
+        if next != as.mode {
+            as.adaptHistory = append(as.adaptHistory, AdaptiveAction{
+                Timestamp:     as.lastAdapt,
+                ActionType:    "mode-switch",
+                Effectiveness: as.metrics.Effectiveness,
+                Impact: map[string]float64{
+                    "from":      float64(as.mode),
+                    "to":        float64(next),
+                    "stability": as.metrics.Stability,
+                },
+            })
+            as.mode = next
+        }
+    }
+    as.trimHistory()
+    onError := as.onError
+    as.mu.Unlock()
+
+    if onError != nil {
+        for _, r := range results {
+            if r.err != nil {
+                onError(r.name, r.err)
+            }
+        }
+    }
+    return errors.Join(errs...)
+}
+
+// trimHistory 丢弃超出容量的最早记录，调用前须持有锁
+func (as *AdaptSystem) trimHistory() {
+    if over := len(as.adaptHistory) - as.historySize; over > 0 {
+        as.adaptHistory = append(as.adaptHistory[:0], as.adaptHistory[over:]...)
+    }
+}
+
+// halts 检查结果是否应中止本周期，passive 模式下任何错误都中止
+func halts(r handlerResult, passive bool) bool {
+    return r.err != nil && (passive || r.entry.policy == HaltOnError)
 }
 
 // naturalAdapt 自然适应过程
-func (as *AdaptSystem) naturalAdapt(ctx *DaoContext, handlers []struct {
-    name    string
-    handler AdaptHandler
-}) error {
-    // 遵循自然规律，平衡阴阳
+func (as *AdaptSystem) naturalAdapt(ctx *DaoContext, handlers []*handlerEntry) []handlerResult {
+    // 遵循自然规律，按优先级依次执行
+    results := make([]handlerResult, 0, len(handlers))
     for _, h := range handlers {
-        if err := h.handler(ctx); err != nil {
-            continue
+        if ctx.Err() != nil {
+            break
+        }
+        result := as.runHandler(ctx, h)
+        results = append(results, result)
+        if halts(result, false) {
+            break
         }
-        // 自然间隔
-        time.Sleep(time.Millisecond * 100)
     }
-    return nil
+    return results
 }
 
 // activeAdapt 主动适应过程
-func (as *AdaptSystem) activeAdapt(ctx *DaoContext, handlers []struct {
-    name    string
-    handler AdaptHandler
-}) error {
-    // 并发执行，快速适应
+func (as *AdaptSystem) activeAdapt(ctx *DaoContext, handlers []*handlerEntry) []handlerResult {
+    // 并发执行，快速适应；中止策略的处理器出错时取消其余处理器
+    cycleCtx, cancel := ctx.WithCancel()
+    defer cancel()
+
+    results := make([]handlerResult, len(handlers))
     var wg sync.WaitGroup
-    for _, h := range handlers {
+    for i, h := range handlers {
         wg.Add(1)
-        go func(handler AdaptHandler) {
+        go func(i int, h *handlerEntry) {
             defer wg.Done()
-            _ = handler(ctx)
-        }(h.handler)
+            results[i] = as.runHandler(cycleCtx, h)
+            if halts(results[i], false) {
+                cancel()
+            }
+        }(i, h)
     }
     wg.Wait()
-    return nil
+    return results
 }
 
 // passiveAdapt 被动适应过程
-func (as *AdaptSystem) passiveAdapt(ctx *DaoContext, handlers []struct {
-    name    string
-    handler AdaptHandler
-}) error {
+func (as *AdaptSystem) passiveAdapt(ctx *DaoContext, handlers []*handlerEntry) []handlerResult {
     // 保守执行，注重稳定
+    results := make([]handlerResult, 0, len(handlers))
     for _, h := range handlers {
-        if err := h.handler(ctx); err != nil {
-            return err // 遇错即停
+        if ctx.Err() != nil {
+            break
+        }
+        result := as.runHandler(ctx, h)
+        results = append(results, result)
+        if halts(result, true) {
+            break // 遇错即停
         }
-        // 较长间隔
-        time.Sleep(time.Millisecond * 200)
     }
-    return nil
+    return results
 }
 
 // isYinDominant 判断是否阴性主导
 func (as *AdaptSystem) isYinDominant() bool {
-    return time.Now().Hour() >= 18 || time.Now().Hour() < 6
+    return as.schedule.IsNight(as.clock.Now())
 }
 
 // getAdaptInterval 获取适应间隔
@@ -0,0 +1,222 @@
+// core/adapt_controller.go
+
+package core
+
+import (
+    "math"
+    "time"
+)
+
+// effectivenessKey 处理器上报效果评分使用的上下文键
+var effectivenessKey = NewKey[float64]("dao.adapt.effectiveness")
+
+// DefaultAdaptHistorySize 默认保留的适应动作数量
+const DefaultAdaptHistorySize = 256
+
+// ReportEffectiveness 处理器上报本次适应的效果评分 (0-1)
+// 未上报时，成功的处理器记为 1，失败记为 0
+func ReportEffectiveness(ctx *DaoContext, score float64) {
+    effectivenessKey.Set(ctx, clampUnit(score))
+}
+
+// AdaptControllerConfig 自适应控制器配置
+type AdaptControllerConfig struct {
+    Window                 int     // 计算稳定性与趋势的周期数
+    MinDwell               int     // 模式切换后至少保持的周期数
+    EffectivenessThreshold float64 // 效果阈值，即 adaptiveThreshold
+    StabilityThreshold     float64 // 稳定性阈值
+    Hysteresis             float64 // 滞回带宽，进入与退出模式的阈值相差 2 倍带宽
+    TrendThreshold         float64 // 效果下降超过此值时转入主动适应
+}
+
+// DefaultAdaptControllerConfig 默认控制器配置
+func DefaultAdaptControllerConfig() AdaptControllerConfig {
+    return AdaptControllerConfig{
+        Window:                 10,
+        MinDwell:               3,
+        EffectivenessThreshold: 0.6,
+        StabilityThreshold:     0.6,
+        Hysteresis:             0.1,
+        TrendThreshold:         0.15,
+    }
+}
+
+// adaptController 根据效果与稳定性趋势选择适应模式
+type adaptController struct {
+    config  AdaptControllerConfig
+    samples []float64 // 最近各周期的效果
+    dwell   int       // 当前模式已保持的周期数
+}
+
+// newAdaptController 创建自适应控制器
+func newAdaptController(config AdaptControllerConfig) *adaptController {
+    defaults := DefaultAdaptControllerConfig()
+    if config.Window < 2 {
+        config.Window = defaults.Window
+    }
+    if config.MinDwell < 1 {
+        config.MinDwell = defaults.MinDwell
+    }
+    if config.Hysteresis < 0 {
+        config.Hysteresis = 0
+    }
+    return &adaptController{
+        config:  config,
+        samples: make([]float64, 0, config.Window),
+    }
+}
+
+// observe 记录一个周期的效果并计算周期指标
+func (c *adaptController) observe(results []handlerResult) AdaptiveMetrics {
+    metrics := cycleMetrics(results)
+    if metrics.Energy == 0 {
+        metrics.Stability = c.stability()
+        return metrics
+    }
+
+    if len(c.samples) == c.config.Window {
+        c.samples = append(c.samples[:0], c.samples[1:]...)
+    }
+    c.samples = append(c.samples, metrics.Effectiveness)
+    c.dwell++
+
+    metrics.Stability = c.stability()
+    return metrics
+}
+
+// decide 按滞回规则决定下一周期的适应模式
+// 被动适应直至稳定性回升到阈值之上，主动适应直至效果回升且不再下降
+func (c *adaptController) decide(current AdaptMode, threshold float64, m AdaptiveMetrics) AdaptMode {
+    if c.dwell < c.config.MinDwell {
+        return current
+    }
+
+    band := c.config.Hysteresis
+    stabilityLow := c.config.StabilityThreshold - band
+    stabilityHigh := c.config.StabilityThreshold + band
+    effectivenessLow := threshold - band
+    effectivenessHigh := threshold + band
+    trend := c.trend()
+
+    next := current
+    switch {
+    case current == PassiveAdapt && m.Stability < stabilityHigh:
+        // 尚未恢复稳定
+    case m.Stability < stabilityLow:
+        next = PassiveAdapt
+    case current == ActiveAdapt && (m.Effectiveness < effectivenessHigh || trend < 0):
+        // 效果尚未恢复
+    case m.Effectiveness < effectivenessLow || trend < -c.config.TrendThreshold:
+        next = ActiveAdapt
+    default:
+        next = NaturalAdapt
+    }
+
+    if next != current {
+        c.dwell = 0
+    }
+    return next
+}
+
+// stability 效果越平稳稳定性越高，标准差 0.5 时为 0
+func (c *adaptController) stability() float64 {
+    if len(c.samples) < 2 {
+        return 1
+    }
+    mean := 0.0
+    for _, s := range c.samples {
+        mean += s
+    }
+    mean /= float64(len(c.samples))
+
+    variance := 0.0
+    for _, s := range c.samples {
+        variance += (s - mean) * (s - mean)
+    }
+    variance /= float64(len(c.samples))
+    return clampUnit(1 - 2*math.Sqrt(variance))
+}
+
+// trend 效果趋势：窗口后半段均值减前半段均值
+func (c *adaptController) trend() float64 {
+    n := len(c.samples)
+    if n < 2 {
+        return 0
+    }
+    half := n / 2
+    var early, late float64
+    for _, s := range c.samples[:half] {
+        early += s
+    }
+    for _, s := range c.samples[n-half:] {
+        late += s
+    }
+    return (late - early) / float64(half)
+}
+
+// handlerResult 处理器单次执行结果
+type handlerResult struct {
+    entry    *handlerEntry
+    name     string
+    score    float64
+    err      error
+    duration time.Duration
+    at       time.Time
+    retries  int
+    timeouts int
+    skipped  bool // 未获得并发名额，未执行
+}
+
+// action 转换为适应动作记录
+func (r handlerResult) action(mode AdaptMode) AdaptiveAction {
+    failed := 0.0
+    if r.err != nil {
+        failed = 1
+    }
+    return AdaptiveAction{
+        Timestamp:     r.at,
+        ActionType:    r.name,
+        Effectiveness: r.score,
+        Impact: map[string]float64{
+            "mode":     float64(mode),
+            "error":    failed,
+            "retries":  float64(r.retries),
+            "duration": r.duration.Seconds(),
+        },
+    }
+}
+
+// cycleMetrics 汇总一个周期的处理器结果
+// Balance 为成功处理器占比，Energy 为本周期执行的处理器数量
+func cycleMetrics(results []handlerResult) AdaptiveMetrics {
+    var total, succeeded, n float64
+    for _, r := range results {
+        if r.skipped {
+            continue
+        }
+        n++
+        total += r.score
+        if r.err == nil {
+            succeeded++
+        }
+    }
+    if n == 0 {
+        return AdaptiveMetrics{}
+    }
+    return AdaptiveMetrics{
+        Effectiveness: total / n,
+        Balance:       succeeded / n,
+        Energy:        n,
+    }
+}
+
+// clampUnit 将数值限制在 0-1
+func clampUnit(v float64) float64 {
+    if math.IsNaN(v) || v < 0 {
+        return 0
+    }
+    if v > 1 {
+        return 1
+    }
+    return v
+}
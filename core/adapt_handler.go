@@ -0,0 +1,304 @@
+// core/adapt_handler.go
+
+package core
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "sort"
+    "time"
+)
+
+// 处理器错误
+var (
+    ErrHandlerNotFound = errors.New("adapt handler not found")
+    ErrHandlerTimeout  = errors.New("adapt handler timed out")
+)
+
+// ErrorPolicy 处理器出错后的处理策略
+type ErrorPolicy uint8
+
+const (
+    ContinueOnError ErrorPolicy = iota // 记录错误，继续执行其他处理器
+    HaltOnError                        // 中止本周期剩余的处理器
+    DisableOnError                     // 停用该处理器，直至重新启用
+)
+
+// HandlerOption 处理器注册选项
+type HandlerOption func(*handlerEntry)
+
+// WithHandlerInterval 设置处理器的最小执行间隔，为 0 时每个周期执行
+func WithHandlerInterval(interval time.Duration) HandlerOption {
+    return func(e *handlerEntry) {
+        if interval >= 0 {
+            e.interval = interval
+        }
+    }
+}
+
+// WithHandlerTimeout 设置单次执行的超时，为 0 时不限制
+func WithHandlerTimeout(timeout time.Duration) HandlerOption {
+    return func(e *handlerEntry) {
+        if timeout >= 0 {
+            e.timeout = timeout
+        }
+    }
+}
+
+// WithPriority 设置优先级，数值大者先执行
+func WithPriority(priority int) HandlerOption {
+    return func(e *handlerEntry) {
+        e.priority = priority
+    }
+}
+
+// WithConcurrency 限制同一处理器同时执行的数量
+// 超时的执行在后台结束前仍占用名额，名额用尽时跳过本周期
+func WithConcurrency(limit int) HandlerOption {
+    return func(e *handlerEntry) {
+        if limit > 0 {
+            e.slots = make(chan struct{}, limit)
+        }
+    }
+}
+
+// WithRetry 设置失败重试次数与初始退避，每次重试退避加倍
+// 超时的执行在退避结束时仍未返回则不再重试，同一处理器不会与被放弃的执行重叠
+func WithRetry(retries int, backoff time.Duration) HandlerOption {
+    return func(e *handlerEntry) {
+        if retries >= 0 {
+            e.retries = retries
+        }
+        if backoff >= 0 {
+            e.backoff = backoff
+        }
+    }
+}
+
+// WithErrorPolicy 设置出错后的处理策略
+func WithErrorPolicy(policy ErrorPolicy) HandlerOption {
+    return func(e *handlerEntry) {
+        e.policy = policy
+    }
+}
+
+// HandlerStats 处理器运行统计
+type HandlerStats struct {
+    Runs          uint64        // 执行次数，不含重试
+    Failures      uint64        // 重试后仍失败的次数
+    Retries       uint64        // 重试次数
+    Timeouts      uint64        // 超时次数
+    Skipped       uint64        // 因并发名额用尽而跳过的次数
+    Disabled      bool          // 是否已停用
+    LastRun       time.Time     // 最近一次执行时间
+    LastDuration  time.Duration // 最近一次执行耗时
+    TotalDuration time.Duration // 累计执行耗时
+    LastError     error         // 最近一次错误
+}
+
+// handlerEntry 已注册的处理器
+type handlerEntry struct {
+    name     string
+    handler  AdaptHandler
+    nature   DaoPhase
+    interval time.Duration
+    timeout  time.Duration
+    priority int
+    retries  int
+    backoff  time.Duration
+    policy   ErrorPolicy
+    slots    chan struct{} // 并发名额，为 nil 时不限制
+    stats    HandlerStats
+}
+
+// due 检查处理器在 now 时是否应执行，调用前须持有锁
+func (e *handlerEntry) due(now time.Time) bool {
+    if e.stats.Disabled {
+        return false
+    }
+    return e.stats.LastRun.IsZero() || now.Sub(e.stats.LastRun) >= e.interval
+}
+
+// acquire 获取并发名额
+func (e *handlerEntry) acquire() bool {
+    if e.slots == nil {
+        return true
+    }
+    select {
+    case e.slots <- struct{}{}:
+        return true
+    default:
+        return false
+    }
+}
+
+// release 归还并发名额
+func (e *handlerEntry) release() {
+    if e.slots != nil {
+        <-e.slots
+    }
+}
+
+// UnregisterHandler 注销处理器，正在执行的调用不受影响
+func (as *AdaptSystem) UnregisterHandler(name string) error {
+    as.mu.Lock()
+    defer as.mu.Unlock()
+
+    if _, exists := as.handlers[name]; !exists {
+        return fmt.Errorf("%w: %s", ErrHandlerNotFound, name)
+    }
+    delete(as.handlers, name)
+    as.yinHandler = removeName(as.yinHandler, name)
+    as.yangHandler = removeName(as.yangHandler, name)
+    return nil
+}
+
+// EnableHandler 重新启用被停用的处理器
+func (as *AdaptSystem) EnableHandler(name string) error {
+    as.mu.Lock()
+    defer as.mu.Unlock()
+
+    entry, exists := as.handlers[name]
+    if !exists {
+        return fmt.Errorf("%w: %s", ErrHandlerNotFound, name)
+    }
+    entry.stats.Disabled = false
+    return nil
+}
+
+// HandlerStats 获取处理器运行统计
+func (as *AdaptSystem) HandlerStats(name string) (HandlerStats, error) {
+    as.mu.RLock()
+    defer as.mu.RUnlock()
+
+    entry, exists := as.handlers[name]
+    if !exists {
+        return HandlerStats{}, fmt.Errorf("%w: %s", ErrHandlerNotFound, name)
+    }
+    return entry.stats, nil
+}
+
+// OnError 设置处理器出错时的回调
+func (as *AdaptSystem) OnError(fn func(name string, err error)) {
+    as.mu.Lock()
+    defer as.mu.Unlock()
+    as.onError = fn
+}
+
+// dueHandlers 按优先级取出本周期应执行的处理器，调用前须持有锁
+func (as *AdaptSystem) dueHandlers(now time.Time) []*handlerEntry {
+    entries := make([]*handlerEntry, 0, len(as.handlers))
+    for _, entry := range as.handlers {
+        if entry.due(now) {
+            entries = append(entries, entry)
+        }
+    }
+    sort.Slice(entries, func(i, j int) bool {
+        if entries[i].priority != entries[j].priority {
+            return entries[i].priority > entries[j].priority
+        }
+        return entries[i].name < entries[j].name
+    })
+    return entries
+}
+
+// runHandler 在独立的上下文中执行处理器，按配置超时与重试，并收集效果评分
+// 超时后被放弃的执行仍在后台运行时不重试，以免与重试的执行并发
+func (as *AdaptSystem) runHandler(ctx *DaoContext, entry *handlerEntry) handlerResult {
+    clk := as.clockOf()
+    result := handlerResult{
+        entry: entry,
+        name:  entry.name,
+        at:    clk.Now(),
+    }
+    backoff := entry.backoff
+    for attempt := 0; ; attempt++ {
+        if !entry.acquire() {
+            // 上一次超时的执行仍占用名额
+            result.skipped = attempt == 0
+            break
+        }
+        hctx := ctx.Clone()
+        finished, err := as.attempt(hctx, entry)
+        result.err = err
+        result.retries = attempt
+        if errors.Is(err, ErrHandlerTimeout) {
+            result.timeouts++
+        }
+
+        if v, ok := effectivenessKey.Get(hctx); ok {
+            result.score = v
+        } else if err == nil {
+            result.score = 1
+        } else {
+            result.score = 0
+        }
+
+        if err == nil || attempt >= entry.retries || ctx.Err() != nil {
+            break
+        }
+        if backoff > 0 {
+            timer := clk.NewTimer(backoff)
+            select {
+            case <-timer.C():
+            case <-ctx.Done():
+                timer.Stop()
+            }
+            backoff *= 2
+        }
+
+        // 被放弃的执行尚未结束
+        select {
+        case <-finished:
+        default:
+            result.duration = clk.Since(result.at)
+            return result
+        }
+    }
+
+    result.duration = clk.Since(result.at)
+    return result
+}
+
+// attempt 执行一次处理器，超时后放弃等待，处理器结束时归还并发名额
+// 返回的通道在处理器返回后关闭，超时时可据此判断被放弃的执行是否仍在运行
+func (as *AdaptSystem) attempt(hctx *DaoContext, entry *handlerEntry) (<-chan struct{}, error) {
+    finished := make(chan struct{})
+    if entry.timeout <= 0 {
+        defer close(finished)
+        defer entry.release()
+        return finished, entry.handler(hctx)
+    }
+
+    runCtx, cancel := context.WithTimeout(hctx.Context, entry.timeout)
+    defer cancel()
+    hctx.Context = runCtx
+
+    done := make(chan error, 1)
+    go func() {
+        defer close(finished)
+        defer entry.release()
+        done <- entry.handler(hctx)
+    }()
+
+    select {
+    case err := <-done:
+        return finished, err
+    case <-runCtx.Done():
+        if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+            return finished, fmt.Errorf("%w after %v", ErrHandlerTimeout, entry.timeout)
+        }
+        return finished, runCtx.Err()
+    }
+}
+
+// removeName 从名称列表中移除
+func removeName(names []string, name string) []string {
+    for i, n := range names {
+        if n == name {
+            return append(names[:i], names[i+1:]...)
+        }
+    }
+    return names
+}
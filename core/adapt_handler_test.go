@@ -0,0 +1,69 @@
+// core/adapt_handler_test.go
+
+package core
+
+import (
+    "context"
+    "errors"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+// registered 注册处理器并返回其条目
+func registered(t *testing.T, as *AdaptSystem, handler AdaptHandler, opts ...HandlerOption) *handlerEntry {
+    t.Helper()
+    if err := as.RegisterHandler("h", handler, PhaseYinYang, opts...); err != nil {
+        t.Fatal(err)
+    }
+    return as.handlers["h"]
+}
+
+func TestTimedOutAttemptIsNotRetriedWhileRunning(t *testing.T) {
+    as := NewAdaptSystem(DefaultInterval)
+    release := make(chan struct{})
+    defer close(release)
+
+    var calls, running, overlap int32
+    entry := registered(t, as, func(*DaoContext) error {
+        atomic.AddInt32(&calls, 1)
+        if atomic.AddInt32(&running, 1) > 1 {
+            atomic.StoreInt32(&overlap, 1)
+        }
+        defer atomic.AddInt32(&running, -1)
+        <-release // 忽略取消，超时后仍在运行
+        return nil
+    }, WithHandlerTimeout(10*time.Millisecond), WithRetry(3, time.Millisecond))
+
+    result := as.runHandler(NewDaoContext(context.Background()), entry)
+    if !errors.Is(result.err, ErrHandlerTimeout) {
+        t.Fatalf("err = %v, want ErrHandlerTimeout", result.err)
+    }
+    if got := atomic.LoadInt32(&calls); got != 1 {
+        t.Fatalf("handler called %d times, want 1", got)
+    }
+    if result.retries != 0 || atomic.LoadInt32(&overlap) != 0 {
+        t.Fatalf("retries = %d, overlap = %d", result.retries, overlap)
+    }
+}
+
+func TestTimedOutAttemptIsRetriedOnceFinished(t *testing.T) {
+    as := NewAdaptSystem(DefaultInterval)
+
+    var calls int32
+    entry := registered(t, as, func(ctx *DaoContext) error {
+        if atomic.AddInt32(&calls, 1) == 1 {
+            <-ctx.Done() // 首次执行超时后随即返回
+            return ctx.Err()
+        }
+        return nil
+    }, WithHandlerTimeout(10*time.Millisecond), WithRetry(1, 20*time.Millisecond))
+
+    result := as.runHandler(NewDaoContext(context.Background()), entry)
+    if result.err != nil {
+        t.Fatalf("err = %v, want nil after retry", result.err)
+    }
+    if got := atomic.LoadInt32(&calls); got != 2 {
+        t.Fatalf("handler called %d times, want 2", got)
+    }
+}
@@ -1,5 +1,8 @@
 package core
 
+import "time"
+
+// AdaptiveMetrics 一个适应周期的指标
 type AdaptiveMetrics struct {
     Effectiveness float64
     Stability     float64
@@ -7,6 +10,7 @@ type AdaptiveMetrics struct {
     Energy        float64
 }
 
+// AdaptiveAction 一次适应动作的记录
 type AdaptiveAction struct {
     Timestamp    time.Time
     ActionType   string
@@ -0,0 +1,185 @@
+// core/clock/clock.go
+
+package clock
+
+import (
+    "context"
+    "sync"
+    "time"
+)
+
+// Clock 时钟抽象，替代直接调用 time 包以便测试与加速模拟
+type Clock interface {
+    Now() time.Time
+    Since(t time.Time) time.Duration
+    NewTicker(d time.Duration) Ticker
+    NewTimer(d time.Duration) Timer
+    Sleep(d time.Duration)
+}
+
+// Ticker 周期触发器
+type Ticker interface {
+    C() <-chan time.Time
+    Stop()
+}
+
+// Timer 单次触发器
+type Timer interface {
+    C() <-chan time.Time
+    Stop() bool
+}
+
+// clockKey 上下文中时钟的键
+type clockKey struct{}
+
+// NewContext 返回携带时钟的上下文
+func NewContext(ctx context.Context, c Clock) context.Context {
+    return context.WithValue(ctx, clockKey{}, c)
+}
+
+// FromContext 获取上下文中的时钟，未设置时返回真实时钟
+func FromContext(ctx context.Context) Clock {
+    if ctx != nil {
+        if c, ok := ctx.Value(clockKey{}).(Clock); ok && c != nil {
+            return c
+        }
+    }
+    return Real()
+}
+
+// realClock 真实时钟
+type realClock struct{}
+
+var system Clock = realClock{}
+
+// Real 获取真实时钟
+func Real() Clock {
+    return system
+}
+
+func (realClock) Now() time.Time                  { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration { return time.Since(t) }
+func (realClock) Sleep(d time.Duration)           { time.Sleep(d) }
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+    return realTicker{time.NewTicker(d)}
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+    return realTimer{time.NewTimer(d)}
+}
+
+type realTicker struct{ t *time.Ticker }
+
+func (r realTicker) C() <-chan time.Time { return r.t.C }
+func (r realTicker) Stop()               { r.t.Stop() }
+
+type realTimer struct{ t *time.Timer }
+
+func (r realTimer) C() <-chan time.Time { return r.t.C }
+func (r realTimer) Stop() bool          { return r.t.Stop() }
+
+// Accelerated 加速时钟，虚拟时间以 factor 倍速流逝
+// 例如 factor 为 8640 时，一天约 10 秒
+type Accelerated struct {
+    origin time.Time // 虚拟起点
+    start  time.Time // 真实起点
+    factor float64
+}
+
+// NewAccelerated 创建从 origin 开始、以 factor 倍速流逝的时钟
+func NewAccelerated(origin time.Time, factor float64) *Accelerated {
+    if factor <= 0 {
+        factor = 1
+    }
+    return &Accelerated{
+        origin: origin,
+        start:  time.Now(),
+        factor: factor,
+    }
+}
+
+// Factor 获取加速倍数
+func (a *Accelerated) Factor() float64 {
+    return a.factor
+}
+
+// Now 获取虚拟当前时间
+func (a *Accelerated) Now() time.Time {
+    return a.origin.Add(time.Duration(float64(time.Since(a.start)) * a.factor))
+}
+
+// Since 获取自 t 起经过的虚拟时长
+func (a *Accelerated) Since(t time.Time) time.Duration {
+    return a.Now().Sub(t)
+}
+
+// Sleep 休眠虚拟时长 d
+func (a *Accelerated) Sleep(d time.Duration) {
+    time.Sleep(a.scale(d))
+}
+
+// NewTicker 创建按虚拟时长 d 触发的周期触发器
+func (a *Accelerated) NewTicker(d time.Duration) Ticker {
+    t := &acceleratedTicker{
+        ticker: time.NewTicker(a.scale(d)),
+        ch:     make(chan time.Time, 1),
+        done:   make(chan struct{}),
+    }
+    go func() {
+        for {
+            select {
+            case <-t.ticker.C:
+                select {
+                case t.ch <- a.Now():
+                default:
+                }
+            case <-t.done:
+                return
+            }
+        }
+    }()
+    return t
+}
+
+// NewTimer 创建在虚拟时长 d 后触发的单次触发器
+func (a *Accelerated) NewTimer(d time.Duration) Timer {
+    ch := make(chan time.Time, 1)
+    t := time.AfterFunc(a.scale(d), func() {
+        ch <- a.Now()
+    })
+    return &acceleratedTimer{timer: t, ch: ch}
+}
+
+// scale 将虚拟时长换算为真实时长
+func (a *Accelerated) scale(d time.Duration) time.Duration {
+    scaled := time.Duration(float64(d) / a.factor)
+    if scaled <= 0 && d > 0 {
+        scaled = 1
+    }
+    return scaled
+}
+
+type acceleratedTicker struct {
+    ticker *time.Ticker
+    ch     chan time.Time
+    done   chan struct{}
+    once   sync.Once
+}
+
+func (t *acceleratedTicker) C() <-chan time.Time { return t.ch }
+
+func (t *acceleratedTicker) Stop() {
+    t.once.Do(func() {
+        t.ticker.Stop()
+        close(t.done)
+    })
+}
+
+type acceleratedTimer struct {
+    timer *time.Timer
+    ch    chan time.Time
+}
+
+func (t *acceleratedTimer) C() <-chan time.Time { return t.ch }
+func (t *acceleratedTimer) Stop() bool          { return t.timer.Stop() }
@@ -0,0 +1,172 @@
+// core/clock/fake.go
+
+package clock
+
+import (
+    "sync"
+    "time"
+)
+
+// Fake 手动推进的时钟，用于确定性测试
+// 时间只在 Advance 或 Set 时变化，到期的触发器按时间顺序依次触发
+type Fake struct {
+    mu      sync.Mutex
+    now     time.Time
+    waiters []*waiter
+}
+
+// waiter 等待触发的触发器
+type waiter struct {
+    when   time.Time
+    period time.Duration // 为 0 时为单次触发
+    ch     chan time.Time
+}
+
+// NewFake 创建从 start 开始的手动时钟
+func NewFake(start time.Time) *Fake {
+    return &Fake{
+        now:     start,
+        waiters: make([]*waiter, 0),
+    }
+}
+
+// Now 获取当前时间
+func (f *Fake) Now() time.Time {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    return f.now
+}
+
+// Since 获取自 t 起经过的时长
+func (f *Fake) Since(t time.Time) time.Duration {
+    return f.Now().Sub(t)
+}
+
+// Sleep 阻塞直到时钟被推进 d
+func (f *Fake) Sleep(d time.Duration) {
+    if d <= 0 {
+        return
+    }
+    <-f.NewTimer(d).C()
+}
+
+// NewTicker 创建周期触发器
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+    if d <= 0 {
+        panic("clock: non-positive interval for NewTicker")
+    }
+    return &fakeTicker{f: f, w: f.add(d, d)}
+}
+
+// NewTimer 创建单次触发器
+func (f *Fake) NewTimer(d time.Duration) Timer {
+    return &fakeTimer{f: f, w: f.add(d, 0)}
+}
+
+// Advance 推进时钟 d，期间到期的触发器依次触发
+func (f *Fake) Advance(d time.Duration) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    f.advanceTo(f.now.Add(d))
+}
+
+// Set 将时钟设为 t，早于当前时间时只修改时间，不触发
+func (f *Fake) Set(t time.Time) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    if t.Before(f.now) {
+        f.now = t
+        return
+    }
+    f.advanceTo(t)
+}
+
+// Waiters 获取等待中的触发器数量，测试可据此确认协程已就绪
+func (f *Fake) Waiters() int {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    return len(f.waiters)
+}
+
+// add 登记触发器
+func (f *Fake) add(d, period time.Duration) *waiter {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    w := &waiter{
+        when:   f.now.Add(d),
+        period: period,
+        ch:     make(chan time.Time, 1),
+    }
+    if d <= 0 {
+        w.ch <- f.now
+        return w
+    }
+    f.waiters = append(f.waiters, w)
+    return w
+}
+
+// remove 移除触发器，返回是否仍在等待，调用前须持有锁
+func (f *Fake) remove(w *waiter) bool {
+    for i, x := range f.waiters {
+        if x == w {
+            f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+            return true
+        }
+    }
+    return false
+}
+
+// advanceTo 推进到 target 并触发到期的触发器，调用前须持有锁
+// 与 time.Ticker 一致，接收方未及时读取时丢弃该次触发
+func (f *Fake) advanceTo(target time.Time) {
+    for {
+        var next *waiter
+        for _, w := range f.waiters {
+            if !w.when.After(target) && (next == nil || w.when.Before(next.when)) {
+                next = w
+            }
+        }
+        if next == nil {
+            break
+        }
+
+        f.now = next.when
+        select {
+        case next.ch <- f.now:
+        default:
+        }
+        if next.period > 0 {
+            next.when = next.when.Add(next.period)
+        } else {
+            f.remove(next)
+        }
+    }
+    f.now = target
+}
+
+type fakeTicker struct {
+    f *Fake
+    w *waiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.w.ch }
+
+func (t *fakeTicker) Stop() {
+    t.f.mu.Lock()
+    defer t.f.mu.Unlock()
+    t.f.remove(t.w)
+}
+
+type fakeTimer struct {
+    f *Fake
+    w *waiter
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.w.ch }
+
+func (t *fakeTimer) Stop() bool {
+    t.f.mu.Lock()
+    defer t.f.mu.Unlock()
+    return t.f.remove(t.w)
+}
@@ -0,0 +1,70 @@
+// core/clock/schedule.go
+
+package clock
+
+import (
+    "context"
+    "time"
+)
+
+// Schedule 昼夜时刻表
+// DayStart 与 NightStart 为距当地午夜的时长，DayStart 晚于 NightStart 时白昼跨越午夜
+type Schedule struct {
+    Location   *time.Location // 时区，为 nil 时使用 time.Local
+    DayStart   time.Duration  // 白昼开始，阳升阴降
+    NightStart time.Duration  // 夜晚开始，阴升阳降
+}
+
+// DefaultSchedule 默认时刻表：当地时间 6 时至 18 时为白昼
+func DefaultSchedule() Schedule {
+    return Schedule{
+        Location:   time.Local,
+        DayStart:   time.Hour * 6,
+        NightStart: time.Hour * 18,
+    }
+}
+
+// IsDay 判断 t 是否处于白昼
+func (s Schedule) IsDay(t time.Time) bool {
+    offset := s.TimeOfDay(t)
+    if s.DayStart <= s.NightStart {
+        return offset >= s.DayStart && offset < s.NightStart
+    }
+    return offset >= s.DayStart || offset < s.NightStart
+}
+
+// IsNight 判断 t 是否处于夜晚
+func (s Schedule) IsNight(t time.Time) bool {
+    return !s.IsDay(t)
+}
+
+// TimeOfDay 获取 t 在时刻表时区内距午夜的时长
+func (s Schedule) TimeOfDay(t time.Time) time.Duration {
+    loc := s.Location
+    if loc == nil {
+        loc = time.Local
+    }
+    t = t.In(loc)
+    return time.Duration(t.Hour())*time.Hour +
+        time.Duration(t.Minute())*time.Minute +
+        time.Duration(t.Second())*time.Second +
+        time.Duration(t.Nanosecond())
+}
+
+// scheduleKey 上下文中时刻表的键
+type scheduleKey struct{}
+
+// NewScheduleContext 返回携带时刻表的上下文
+func NewScheduleContext(ctx context.Context, s Schedule) context.Context {
+    return context.WithValue(ctx, scheduleKey{}, s)
+}
+
+// ScheduleFromContext 获取上下文中的时刻表，未设置时返回默认时刻表
+func ScheduleFromContext(ctx context.Context) Schedule {
+    if ctx != nil {
+        if s, ok := ctx.Value(scheduleKey{}).(Schedule); ok {
+            return s
+        }
+    }
+    return DefaultSchedule()
+}
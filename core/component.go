@@ -0,0 +1,275 @@
+// core/component.go
+
+package core
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "sync"
+    "time"
+)
+
+// 组件错误
+var (
+    ErrComponentExists    = errors.New("component already registered")
+    ErrComponentNotFound  = errors.New("component not found")
+    ErrMissingDependency  = errors.New("missing component dependency")
+    ErrDependencyCycle    = errors.New("component dependency cycle")
+    ErrComponentTimeout   = errors.New("component start timed out")
+    ErrComponentsRunning  = errors.New("components already started")
+    ErrComponentsStarting = errors.New("components are starting")
+)
+
+// DefaultStartTimeout 组件默认启动超时
+const DefaultStartTimeout = time.Second * 30
+
+// ComponentOption 组件注册选项
+type ComponentOption func(*componentEntry)
+
+// DependsOn 声明组件依赖，依赖组件先于本组件启动、后于本组件停止
+func DependsOn(names ...string) ComponentOption {
+    return func(e *componentEntry) {
+        e.deps = append(e.deps, names...)
+    }
+}
+
+// WithStartTimeout 设置组件的初始化与启动超时
+func WithStartTimeout(timeout time.Duration) ComponentOption {
+    return func(e *componentEntry) {
+        if timeout > 0 {
+            e.timeout = timeout
+        }
+    }
+}
+
+// componentEntry 已注册的组件
+type componentEntry struct {
+    name      string
+    component Component
+    deps      []string
+    timeout   time.Duration
+}
+
+// ComponentRegistry 组件注册表，按依赖顺序启动并逆序停止
+type ComponentRegistry struct {
+    mu       sync.Mutex
+    entries  map[string]*componentEntry
+    order    []string // 注册顺序
+    started  []string // 实际启动顺序
+    starting bool     // Start 正在进行
+}
+
+// NewComponentRegistry 创建组件注册表
+func NewComponentRegistry() *ComponentRegistry {
+    return &ComponentRegistry{
+        entries: make(map[string]*componentEntry),
+        order:   make([]string, 0),
+        started: make([]string, 0),
+    }
+}
+
+// Register 注册组件
+func (r *ComponentRegistry) Register(name string, c Component, opts ...ComponentOption) error {
+    if c == nil {
+        return errors.New("component cannot be nil")
+    }
+
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    if _, exists := r.entries[name]; exists {
+        return fmt.Errorf("%w: %s", ErrComponentExists, name)
+    }
+
+    entry := &componentEntry{
+        name:      name,
+        component: c,
+        deps:      make([]string, 0),
+        timeout:   DefaultStartTimeout,
+    }
+    for _, opt := range opts {
+        opt(entry)
+    }
+
+    r.entries[name] = entry
+    r.order = append(r.order, name)
+    return nil
+}
+
+// Get 获取组件
+func (r *ComponentRegistry) Get(name string) (Component, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    entry, exists := r.entries[name]
+    if !exists {
+        return nil, fmt.Errorf("%w: %s", ErrComponentNotFound, name)
+    }
+    return entry.component, nil
+}
+
+// StartOrder 计算组件的拓扑启动顺序，无依赖关系的组件保持注册顺序
+func (r *ComponentRegistry) StartOrder() ([]string, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    return r.startOrder()
+}
+
+// startOrder 计算启动顺序，调用前须持有锁
+func (r *ComponentRegistry) startOrder() ([]string, error) {
+    pending := make(map[string]int, len(r.entries))
+    dependents := make(map[string][]string, len(r.entries))
+    for _, name := range r.order {
+        entry := r.entries[name]
+        pending[name] = len(entry.deps)
+        for _, dep := range entry.deps {
+            if _, exists := r.entries[dep]; !exists {
+                return nil, fmt.Errorf("%w: %s requires %s", ErrMissingDependency, name, dep)
+            }
+            dependents[dep] = append(dependents[dep], name)
+        }
+    }
+
+    order := make([]string, 0, len(r.order))
+    done := make(map[string]bool, len(r.order))
+    for len(order) < len(r.order) {
+        progressed := false
+        for _, name := range r.order {
+            if done[name] || pending[name] > 0 {
+                continue
+            }
+            done[name] = true
+            order = append(order, name)
+            for _, dependent := range dependents[name] {
+                pending[dependent]--
+            }
+            progressed = true
+            break
+        }
+        if !progressed {
+            blocked := make([]string, 0)
+            for _, name := range r.order {
+                if !done[name] {
+                    blocked = append(blocked, name)
+                }
+            }
+            return nil, fmt.Errorf("%w: %v", ErrDependencyCycle, blocked)
+        }
+    }
+    return order, nil
+}
+
+// Start 按依赖顺序初始化并启动全部组件
+// 调用组件时不持有注册表的锁，组件可在启动过程中调用 Get 与 Started。
+// 任一组件失败或超时时，Start 成功返回的组件按逆序停止，注册表回到未启动状态；
+// 失败的组件不会被停止。超时的组件被放弃：其启动协程可能仍在后台运行，
+// 注册表不等待它返回，也不会对其调用 Stop，组件应在 ctx 取消后自行清理
+func (r *ComponentRegistry) Start(ctx context.Context) error {
+    r.mu.Lock()
+    if r.starting {
+        r.mu.Unlock()
+        return ErrComponentsStarting
+    }
+    if len(r.started) > 0 {
+        r.mu.Unlock()
+        return ErrComponentsRunning
+    }
+    order, err := r.startOrder()
+    if err != nil {
+        r.mu.Unlock()
+        return err
+    }
+    entries := make([]*componentEntry, 0, len(order))
+    for _, name := range order {
+        entries = append(entries, r.entries[name])
+    }
+    r.starting = true
+    r.mu.Unlock()
+
+    for _, entry := range entries {
+        if err := r.startEntry(ctx, entry); err != nil {
+            startErr := fmt.Errorf("start component %s: %w", entry.name, err)
+            stopErr := r.stopAll(ctx)
+            r.mu.Lock()
+            r.starting = false
+            r.mu.Unlock()
+            if stopErr != nil {
+                return errors.Join(startErr, stopErr)
+            }
+            return startErr
+        }
+        r.mu.Lock()
+        r.started = append(r.started, entry.name)
+        r.mu.Unlock()
+    }
+
+    r.mu.Lock()
+    r.starting = false
+    r.mu.Unlock()
+    return nil
+}
+
+// startEntry 在超时限制内初始化并启动单个组件
+// 超时时立即返回，不等待启动协程结束
+func (r *ComponentRegistry) startEntry(ctx context.Context, entry *componentEntry) error {
+    startCtx, cancel := context.WithTimeout(ctx, entry.timeout)
+    defer cancel()
+
+    result := make(chan error, 1)
+    go func() {
+        if err := entry.component.Init(startCtx); err != nil {
+            result <- err
+            return
+        }
+        result <- entry.component.Start(startCtx)
+    }()
+
+    select {
+    case err := <-result:
+        return err
+    case <-startCtx.Done():
+        if errors.Is(startCtx.Err(), context.DeadlineExceeded) {
+            return fmt.Errorf("%w after %v", ErrComponentTimeout, entry.timeout)
+        }
+        return startCtx.Err()
+    }
+}
+
+// Stop 按启动的逆序停止全部组件，启动过程中调用时返回 ErrComponentsStarting
+func (r *ComponentRegistry) Stop(ctx context.Context) error {
+    r.mu.Lock()
+    starting := r.starting
+    r.mu.Unlock()
+    if starting {
+        return ErrComponentsStarting
+    }
+    return r.stopAll(ctx)
+}
+
+// stopAll 逆序停止已启动的组件，调用组件时不持有锁
+func (r *ComponentRegistry) stopAll(ctx context.Context) error {
+    r.mu.Lock()
+    started := r.started
+    r.started = make([]string, 0)
+    entries := make([]*componentEntry, 0, len(started))
+    for _, name := range started {
+        entries = append(entries, r.entries[name])
+    }
+    r.mu.Unlock()
+
+    var errs []error
+    for i := len(entries) - 1; i >= 0; i-- {
+        if err := entries[i].component.Stop(ctx); err != nil {
+            errs = append(errs, fmt.Errorf("stop component %s: %w", entries[i].name, err))
+        }
+    }
+    return errors.Join(errs...)
+}
+
+// Started 获取已启动组件，按启动顺序排列
+func (r *ComponentRegistry) Started() []string {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    return append([]string(nil), r.started...)
+}
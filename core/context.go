@@ -4,10 +4,14 @@ package core
 
 import (
     "context"
+    "errors"
     "sync"
     "time"
 )
 
+// ErrInvalidBalance 阴阳失衡超出允许范围
+var ErrInvalidBalance = errors.New("yin yang balance out of range")
+
 // DaoPhase 代表事物所处的阶段
 type DaoPhase uint8
 
@@ -31,7 +35,8 @@ type DaoContext struct {
     mu         sync.RWMutex
     phase      DaoPhase                 // 当前阶段
     attributes *DaoAttribute            // 阴阳属性
-    values     map[string]interface{}   // 存储值
+    values     map[string]valueEntry    // 存储值，可能与父子上下文共享
+    shared     bool                     // values 已共享，写入前须复制
     birth      time.Time                // 创建时间
     timeout    time.Duration            // 新增超时控制
     cancel    context.CancelFunc        // 新增取消函数
@@ -47,7 +52,7 @@ func NewDaoContext(ctx context.Context) *DaoContext {
         Context:    ctx,
         phase:      PhaseWuJi,
         attributes: &DaoAttribute{Yin: 50, Yang: 50}, // 初始平衡
-        values:     make(map[string]interface{}),
+        values:     make(map[string]valueEntry),
         birth:      time.Now(),
     }
 }
@@ -66,23 +71,23 @@ func (dc *DaoContext) GetPhase() DaoPhase {
     return dc.phase
 }
 
-// SetValue 设置值
+// SetValue 设置本地值，类型化的值请使用 Key
 func (dc *DaoContext) SetValue(key string, value interface{}) {
     dc.mu.Lock()
     defer dc.mu.Unlock()
-    dc.values[key] = value
+    dc.writableValues()[key] = valueEntry{value: value}
 }
 
 // GetValue 获取值
 func (dc *DaoContext) GetValue(key string) (interface{}, bool) {
     dc.mu.RLock()
     defer dc.mu.RUnlock()
-    value, exists := dc.values[key]
-    return value, exists
+    entry, exists := dc.values[key]
+    return entry.value, exists
 }
 
 // AdjustAttribute 调整阴阳属性
-func (dc *DaoContext) AdjustAttribute(yinDelta, yangDelta int16) {
+func (dc *DaoContext) AdjustAttribute(yinDelta, yangDelta int16) error {
     dc.mu.Lock()
     defer dc.mu.Unlock()
     
@@ -109,6 +114,7 @@ func (dc *DaoContext) AdjustAttribute(yinDelta, yangDelta int16) {
     
     dc.attributes.Yin = uint8(newYin)
     dc.attributes.Yang = uint8(newYang)
+    return nil
 }
 
 // [新增] 阴阳平衡检查
@@ -135,45 +141,46 @@ func (dc *DaoContext) Age() time.Duration {
 // WithTimeout 创建具有超时的新上下文
 func (dc *DaoContext) WithTimeout(timeout time.Duration) (*DaoContext, context.CancelFunc) {
     ctx, cancel := context.WithTimeout(dc.Context, timeout)
-    newCtx := NewDaoContext(ctx)
-    // 继承原上下文的属性
-    newCtx.phase = dc.phase
-    newCtx.attributes = &DaoAttribute{
-        Yin:  dc.attributes.Yin,
-        Yang: dc.attributes.Yang,
-    }
+    newCtx := dc.child(ctx)
+    newCtx.timeout = timeout
+    newCtx.cancel = cancel
     return newCtx, cancel
 }
 
 // WithCancel 创建可取消的新上下文
 func (dc *DaoContext) WithCancel() (*DaoContext, context.CancelFunc) {
     ctx, cancel := context.WithCancel(dc.Context)
-    newCtx := NewDaoContext(ctx)
-    // 继承原上下文的属性
-    newCtx.phase = dc.phase
-    newCtx.attributes = &DaoAttribute{
-        Yin:  dc.attributes.Yin,
-        Yang: dc.attributes.Yang,
-    }
+    newCtx := dc.child(ctx)
+    newCtx.cancel = cancel
     return newCtx, cancel
 }
 
+// wrap 以 f 包装底层 context，用于在构造阶段注入时钟等值
+// 底层 context 的读取不加锁，只应在上下文开始被并发使用之前调用
+func (dc *DaoContext) wrap(f func(context.Context) context.Context) {
+    dc.mu.Lock()
+    defer dc.mu.Unlock()
+    dc.Context = f(dc.Context)
+}
+
 // Clone 创建上下文的克隆
 func (dc *DaoContext) Clone() *DaoContext {
-    dc.mu.RLock()
-    defer dc.mu.RUnlock()
-    
-    newCtx := NewDaoContext(dc.Context)
+    return dc.child(dc.Context)
+}
+
+// child 基于 ctx 创建子上下文，继承阶段、属性与值
+// 值表在父子之间共享，任一方写入时复制
+func (dc *DaoContext) child(ctx context.Context) *DaoContext {
+    dc.mu.Lock()
+    defer dc.mu.Unlock()
+
+    newCtx := NewDaoContext(ctx)
     newCtx.phase = dc.phase
     newCtx.attributes = &DaoAttribute{
         Yin:  dc.attributes.Yin,
         Yang: dc.attributes.Yang,
     }
-    
-    // 复制值
-    for k, v := range dc.values {
-        newCtx.values[k] = v
-    }
-    
+    newCtx.values = dc.shareValues()
+    newCtx.shared = true
     return newCtx
 }
@@ -0,0 +1,193 @@
+// core/context_values.go
+
+package core
+
+import (
+    "encoding/json"
+    "fmt"
+)
+
+// Key 类型化的上下文键
+// 值由父上下文继承到子上下文；传播键的值可序列化并跨进程传递，本地键的值只在进程内可见
+type Key[T any] struct {
+    name      string
+    propagate bool
+}
+
+// NewKey 创建本地键
+func NewKey[T any](name string) Key[T] {
+    return Key[T]{name: name}
+}
+
+// NewPropagatingKey 创建传播键，值须可 JSON 编码
+func NewPropagatingKey[T any](name string) Key[T] {
+    return Key[T]{name: name, propagate: true}
+}
+
+// Name 获取键名
+func (k Key[T]) Name() string {
+    return k.name
+}
+
+// Propagating 检查是否为传播键
+func (k Key[T]) Propagating() bool {
+    return k.propagate
+}
+
+// Set 在上下文中设置值，不影响父上下文与已创建的子上下文
+func (k Key[T]) Set(dc *DaoContext, value T) {
+    dc.mu.Lock()
+    defer dc.mu.Unlock()
+    dc.writableValues()[k.name] = valueEntry{value: value, propagate: k.propagate}
+}
+
+// Get 获取值，自其他进程传入的值在首次读取时解码并缓存
+// 以不同类型读取已缓存的值时按原始编码重新解码，不替换缓存
+func (k Key[T]) Get(dc *DaoContext) (T, bool) {
+    var zero T
+
+    dc.mu.RLock()
+    entry, exists := dc.values[k.name]
+    dc.mu.RUnlock()
+    if !exists {
+        return zero, false
+    }
+
+    if raw, ok := entry.value.(rawValue); ok {
+        var v T
+        if err := json.Unmarshal(raw, &v); err != nil {
+            return zero, false
+        }
+        dc.cacheDecoded(k.name, raw, v)
+        return v, true
+    }
+    if v, ok := entry.value.(T); ok {
+        return v, true
+    }
+    if entry.raw != nil {
+        var v T
+        if err := json.Unmarshal(entry.raw, &v); err != nil {
+            return zero, false
+        }
+        return v, true
+    }
+    return zero, false
+}
+
+// Delete 删除值
+func (k Key[T]) Delete(dc *DaoContext) {
+    dc.mu.Lock()
+    defer dc.mu.Unlock()
+    if _, exists := dc.values[k.name]; exists {
+        delete(dc.writableValues(), k.name)
+    }
+}
+
+// valueEntry 上下文中的值
+type valueEntry struct {
+    value     interface{}
+    raw       rawValue // 自其他进程传入的原始编码，解码后保留
+    propagate bool
+}
+
+// rawValue 尚未解码的传播值
+type rawValue json.RawMessage
+
+// cacheDecoded 以解码后的值替换尚未解码的传播值，值在解码期间被修改时放弃
+// 值表与子上下文共享时先复制，不影响其他上下文
+func (dc *DaoContext) cacheDecoded(name string, raw rawValue, value interface{}) {
+    dc.mu.Lock()
+    defer dc.mu.Unlock()
+
+    entry, exists := dc.values[name]
+    if !exists {
+        return
+    }
+    current, ok := entry.value.(rawValue)
+    if !ok || len(current) != len(raw) || (len(raw) > 0 && &current[0] != &raw[0]) {
+        return
+    }
+    dc.writableValues()[name] = valueEntry{value: value, raw: raw, propagate: entry.propagate}
+}
+
+// shareValues 与子上下文共享值表，调用前须持有写锁
+func (dc *DaoContext) shareValues() map[string]valueEntry {
+    dc.shared = true
+    return dc.values
+}
+
+// writableValues 获取可写的值表，共享时先复制，调用前须持有写锁
+func (dc *DaoContext) writableValues() map[string]valueEntry {
+    if dc.shared {
+        values := make(map[string]valueEntry, len(dc.values)+1)
+        for k, v := range dc.values {
+            values[k] = v
+        }
+        dc.values = values
+        dc.shared = false
+    }
+    return dc.values
+}
+
+// PropagatingValues 获取全部传播值的 JSON 编码
+func (dc *DaoContext) PropagatingValues() (map[string]json.RawMessage, error) {
+    dc.mu.RLock()
+    defer dc.mu.RUnlock()
+
+    encoded := make(map[string]json.RawMessage)
+    for name, entry := range dc.values {
+        if !entry.propagate {
+            continue
+        }
+        if raw, ok := entry.value.(rawValue); ok {
+            encoded[name] = json.RawMessage(raw)
+            continue
+        }
+        if entry.raw != nil {
+            encoded[name] = json.RawMessage(entry.raw)
+            continue
+        }
+        data, err := json.Marshal(entry.value)
+        if err != nil {
+            return nil, fmt.Errorf("encode context value %s: %w", name, err)
+        }
+        encoded[name] = data
+    }
+    return encoded, nil
+}
+
+// SetPropagatingValues 写入自其他进程传入的传播值，值在 Key.Get 时解码
+func (dc *DaoContext) SetPropagatingValues(values map[string]json.RawMessage) {
+    if len(values) == 0 {
+        return
+    }
+    dc.mu.Lock()
+    defer dc.mu.Unlock()
+
+    writable := dc.writableValues()
+    for name, data := range values {
+        writable[name] = valueEntry{
+            value:     rawValue(append(json.RawMessage(nil), data...)),
+            propagate: true,
+        }
+    }
+}
+
+// MarshalValues 序列化全部传播值
+func (dc *DaoContext) MarshalValues() ([]byte, error) {
+    values, err := dc.PropagatingValues()
+    if err != nil {
+        return nil, err
+    }
+    return json.Marshal(values)
+}
+
+// UnmarshalValues 反序列化 MarshalValues 的结果并写入上下文
+func (dc *DaoContext) UnmarshalValues(data []byte) error {
+    var values map[string]json.RawMessage
+    if err := json.Unmarshal(data, &values); err != nil {
+        return fmt.Errorf("decode context values: %w", err)
+    }
+    dc.SetPropagatingValues(values)
+    return nil
+}
@@ -5,6 +5,13 @@ package core
 import (
     "context"
     "errors"
+    "fmt"
+    "strconv"
+    "strings"
+    "sync"
+
+    "github.com/Corphon/daoframe/core/force"
+    "github.com/Corphon/daoframe/core/state"
 )
 
 // 定义核心错误类型
@@ -15,14 +22,17 @@ var (
     ErrInvalidForce   = errors.New("invalid force application")
 )
 
-// Force 表示道的作用力
-type Force uint8
+// Force 表示道的作用力，其组合规则由 force.Algebra 定义
+type Force = force.Force
+
+// ForceVector 带强度的作用力
+type ForceVector = force.Vector
 
 const (
-    ForceCreate  Force = iota + 1 // 生之力
-    ForceDestroy                  // 灭之力
-    ForceTransform                // 变之力
-    ForceBalance                  // 衡之力
+    ForceCreate    = force.Create    // 生之力
+    ForceDestroy   = force.Destroy   // 灭之力
+    ForceTransform = force.Transform // 变之力
+    ForceBalance   = force.Balance   // 衡之力
 )
 
 // DaoSource 定义了道源的核心接口
@@ -40,73 +50,121 @@ type DaoSource interface {
     Terminate(ctx context.Context) error
     
     // GetState 获取当前状态
-    GetState() State
+    GetState() state.State
 }
 
 // BaseDaoSource 提供 DaoSource 接口的基本实现
 type BaseDaoSource struct {
-    state     State
+    mu        sync.RWMutex
+    states    *state.StateManager // 状态由共享状态机驱动
     essence   interface{} // 本质
-    force     Force      // 当前作用力
+    force     ForceVector    // 当前作用力
+    algebra   *force.Algebra // 作用力代数
+    journal   *state.Journal // 变化日志
+    subject   string         // 日志主体标识
 }
 
 // NewBaseDaoSource 创建新的道源基础实现
 func NewBaseDaoSource() *BaseDaoSource {
     return &BaseDaoSource{
-        state: StateVoid,
-        force: ForceCreate,
+        states:  state.NewStateManager(state.BaseMachine()),
+        force:   ForceVector{Force: ForceCreate, Magnitude: MaximumForce},
+        algebra: force.NewDefaultAlgebra(),
     }
 }
 
 // Initialize 实现基本的初始化
 func (b *BaseDaoSource) Initialize(ctx context.Context) error {
-    if b.state != StateVoid {
-        return ErrInvalidState
-    }
-    b.state = StateInactive
-    return nil
+    return b.transit(state.StateInactive)
 }
 
 // Activate 激活道源
 func (b *BaseDaoSource) Activate(ctx context.Context) error {
-    if b.state != StateInactive {
-        return ErrInvalidState
+    return b.transit(state.StateActive)
+}
+
+// ApplyForce 实现力的作用，以最大强度施加单一作用力
+func (b *BaseDaoSource) ApplyForce(f Force) error {
+    return b.ApplyForcesBy(state.Cause{}, ForceVector{Force: f, Magnitude: MaximumForce})
+}
+
+// ApplyForceBy 以指定原因与发起者施加单一作用力
+func (b *BaseDaoSource) ApplyForceBy(f Force, cause state.Cause) error {
+    return b.ApplyForcesBy(cause, ForceVector{Force: f, Magnitude: MaximumForce})
+}
+
+// ApplyForces 同时施加一组作用力
+func (b *BaseDaoSource) ApplyForces(vs ...ForceVector) error {
+    return b.ApplyForcesBy(state.Cause{}, vs...)
+}
+
+// ApplyForcesBy 以指定原因与发起者同时施加一组作用力
+// 作用力先按代数合成，再与当前作用力按冲突策略结合；每次调用均写入日志
+func (b *BaseDaoSource) ApplyForcesBy(cause state.Cause, vs ...ForceVector) error {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+
+    next, err := b.evaluateForces(vs)
+    if jerr := b.recordForces(vs, next, cause, err); jerr != nil {
+        return jerr
+    }
+    if err != nil {
+        return err
     }
-    b.state = StateActive
+
+    b.force = next
     return nil
 }
 
-// ApplyForce 实现力的作用
-func (b *BaseDaoSource) ApplyForce(force Force) error {
-    if b.state != StateActive {
-        return ErrInvalidState
+// evaluateForces 计算施加作用力后的结果
+func (b *BaseDaoSource) evaluateForces(vs []ForceVector) (ForceVector, error) {
+    if !b.states.Is(state.StateActive) {
+        return b.force, ErrInvalidState
     }
-    
-    switch force {
-    case ForceCreate:
-        if b.force == ForceDestroy {
-            return ErrInvalidForce
-        }
-    case ForceDestroy:
-        if b.force == ForceCreate {
-            return ErrInvalidForce
-        }
-    case ForceTransform:
-        // 变化之力可以在任何状态下使用
-    case ForceBalance:
-        // 平衡之力可以化解对立
-        b.force = ForceBalance
-    default:
-        return ErrInvalidForce
+
+    next, err := b.algebra.Apply(b.force, vs...)
+    if err != nil {
+        return b.force, fmt.Errorf("%w: %v", ErrInvalidForce, err)
     }
-    
-    b.force = force
-    return nil
+    return next, nil
+}
+
+// recordForces 将作用力施加写入日志
+func (b *BaseDaoSource) recordForces(vs []ForceVector, next ForceVector, cause state.Cause, applyErr error) error {
+    if b.journal == nil {
+        return nil
+    }
+
+    applied := make([]string, 0, len(vs))
+    for _, v := range vs {
+        applied = append(applied, fmt.Sprintf("%d:%g", uint8(v.Force), v.Magnitude))
+    }
+
+    detail := map[string]string{
+        "applied":   strings.Join(applied, ","),
+        "previous":  strconv.Itoa(int(b.force.Force)),
+        "force":     strconv.Itoa(int(next.Force)),
+        "magnitude": strconv.FormatFloat(next.Magnitude, 'g', -1, 64),
+        "result":    "applied",
+    }
+    if applyErr != nil {
+        detail["result"] = "rejected"
+        detail["error"] = applyErr.Error()
+    }
+
+    _, err := b.journal.Append(context.Background(), state.JournalEntry{
+        Subject: b.subject,
+        Kind:    state.EntryForce,
+        Cause:   cause.Reason,
+        Actor:   cause.Actor,
+        Detail:  detail,
+    })
+    return err
 }
 
 // Adapt 实现基本的适应机制
 func (b *BaseDaoSource) Adapt(ctx context.Context) error {
-    if b.state != StateActive {
+    if !b.states.Is(state.StateActive) {
         return ErrInvalidState
     }
     return nil
@@ -114,37 +172,97 @@ func (b *BaseDaoSource) Adapt(ctx context.Context) error {
 
 // Terminate 实现基本的终止逻辑
 func (b *BaseDaoSource) Terminate(ctx context.Context) error {
-    if b.state == StateTerminated {
-        return ErrInvalidState
-    }
-    b.state = StateTerminated
-    return nil
+    return b.transit(state.StateTerminated)
 }
 
 // GetState 获取当前状态
-func (b *BaseDaoSource) GetState() State {
-    return b.state
+func (b *BaseDaoSource) GetState() state.State {
+    return b.states.Current()
+}
+
+// StateManager 获取道源的状态管理器
+func (b *BaseDaoSource) StateManager() *state.StateManager {
+    return b.states
 }
 
 // GetForce 获取当前作用力
 func (b *BaseDaoSource) GetForce() Force {
+    b.mu.RLock()
+    defer b.mu.RUnlock()
+    return b.force.Force
+}
+
+// GetForceVector 获取当前作用力及其强度
+func (b *BaseDaoSource) GetForceVector() ForceVector {
+    b.mu.RLock()
+    defer b.mu.RUnlock()
     return b.force
 }
 
-// isValidTransition 检查状态转换是否有效
-func isValidTransition(current, new State) bool {
-    switch current {
-    case StateVoid:
-        return new == StateInactive
-    case StateInactive:
-        return new == StateActive || new == StateVoid
-    case StateActive:
-        return new == StatePaused || new == StateTerminated || new == StateInactive
-    case StatePaused:
-        return new == StateActive || new == StateTerminated
-    case StateTerminated:
-        return new == StateVoid // 允许重新归于虚无
-    default:
-        return false
+// SetForceAlgebra 设置作用力代数，用于注册自定义作用力及其规则
+func (b *BaseDaoSource) SetForceAlgebra(a *force.Algebra) {
+    if a == nil {
+        return
+    }
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    b.algebra = a
+}
+
+// ForceAlgebra 获取作用力代数
+func (b *BaseDaoSource) ForceAlgebra() *force.Algebra {
+    b.mu.RLock()
+    defer b.mu.RUnlock()
+    return b.algebra
+}
+
+// AttachJournal 挂接日志，状态转换与作用力施加均写入同一主体
+func (b *BaseDaoSource) AttachJournal(j *state.Journal, subject string) {
+    b.mu.Lock()
+    b.journal = j
+    b.subject = subject
+    b.mu.Unlock()
+
+    b.states.AttachJournal(j, subject)
+}
+
+// Replay 从日志重建道源的状态与当前作用力
+func (b *BaseDaoSource) Replay(ctx context.Context) error {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+
+    if b.journal == nil {
+        return state.ErrNoJournal
+    }
+    if err := b.states.Replay(ctx); err != nil {
+        return err
+    }
+
+    entries, err := b.journal.Entries(ctx, b.subject, 0)
+    if err != nil {
+        return err
     }
+    for _, entry := range entries {
+        if entry.Kind != state.EntryForce || entry.Detail["result"] != "applied" {
+            continue
+        }
+        value, err := strconv.Atoi(entry.Detail["force"])
+        if err != nil {
+            return fmt.Errorf("%w: %s#%d: %v", state.ErrJournalMismatch, entry.Subject, entry.Sequence, err)
+        }
+        magnitude, err := strconv.ParseFloat(entry.Detail["magnitude"], 64)
+        if err != nil {
+            return fmt.Errorf("%w: %s#%d: %v", state.ErrJournalMismatch, entry.Subject, entry.Sequence, err)
+        }
+        b.force = ForceVector{Force: Force(value), Magnitude: magnitude}
+    }
+    return nil
+}
+
+// transit 通过状态机执行转换
+func (b *BaseDaoSource) transit(to state.State) error {
+    if err := b.states.TransitTo(to); err != nil {
+        return fmt.Errorf("%w: %v", ErrInvalidState, err)
+    }
+    return nil
 }
@@ -0,0 +1,282 @@
+package force
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "sync"
+)
+
+// 作用力代数错误
+var (
+    ErrUnknownForce     = errors.New("unknown force")
+    ErrInvalidMagnitude = errors.New("invalid force magnitude")
+    ErrForceConflict    = errors.New("conflicting forces")
+    ErrEmptyVector      = errors.New("empty force vector")
+)
+
+// Resolution 冲突解决策略
+type Resolution uint8
+
+const (
+    ResolveReject   Resolution = iota // 拒绝后来的力
+    ResolveOverride                   // 后来的力覆盖
+    ResolveStronger                   // 强者胜，强度相等时拒绝
+    ResolveCancel                     // 相互抵消，强度相减且不低于 MinMagnitude；完全抵消时归于衡
+    ResolveCompose                    // 按合成规则合成
+)
+
+// ComposeRule 两力同时作用时的合成规则
+type ComposeRule func(a, b Vector) Vector
+
+// Spec 作用力声明
+type Spec struct {
+    Force        Force
+    Name         string
+    MaxMagnitude float64 // 为 0 或超过 MaxMagnitude 时取 MaxMagnitude
+}
+
+// pair 无序力对
+type pair struct {
+    a, b Force
+}
+
+// pairOf 规范化力对
+func pairOf(a, b Force) pair {
+    if a > b {
+        a, b = b, a
+    }
+    return pair{a, b}
+}
+
+// Algebra 可注册的作用力代数
+type Algebra struct {
+    mu           sync.RWMutex
+    specs        map[Force]Spec
+    conflicts    map[pair]Resolution
+    compositions map[pair]ComposeRule
+}
+
+// NewAlgebra 创建空的作用力代数
+func NewAlgebra() *Algebra {
+    return &Algebra{
+        specs:        make(map[Force]Spec),
+        conflicts:    make(map[pair]Resolution),
+        compositions: make(map[pair]ComposeRule),
+    }
+}
+
+// NewDefaultAlgebra 创建注册了内置作用力的代数
+// 冲突关系由 ForceInteraction 推导，默认拒绝冲突
+func NewDefaultAlgebra() *Algebra {
+    a := NewAlgebra()
+    builtins := []Spec{
+        {Force: Create, Name: "Create"},
+        {Force: Destroy, Name: "Destroy"},
+        {Force: Transform, Name: "Transform"},
+        {Force: Balance, Name: "Balance"},
+    }
+    for _, spec := range builtins {
+        a.register(spec)
+    }
+
+    for _, x := range builtins {
+        for _, y := range builtins {
+            if x.Force < y.Force && !interacts(x.Force, y.Force) {
+                a.SetConflict(x.Force, y.Force, ResolveReject)
+            }
+        }
+    }
+    return a
+}
+
+// interacts 按 ForceInteraction 检查两力能否共存
+func interacts(x, y Force) bool {
+    for _, f := range ForceInteraction[x] {
+        if f == y {
+            return true
+        }
+    }
+    return false
+}
+
+// Register 注册自定义作用力，取值须不小于 FirstCustom，内置作用力不可被覆盖
+func (a *Algebra) Register(spec Spec) error {
+    if spec.Force < FirstCustom {
+        return fmt.Errorf("%w: %v is reserved", ErrUnknownForce, spec.Force)
+    }
+    a.register(spec)
+    return nil
+}
+
+// register 注册作用力，不检查保留取值
+func (a *Algebra) register(spec Spec) {
+    if spec.MaxMagnitude <= 0 || spec.MaxMagnitude > MaxMagnitude {
+        spec.MaxMagnitude = MaxMagnitude
+    }
+    if spec.Name == "" {
+        spec.Name = spec.Force.String()
+    }
+
+    a.mu.Lock()
+    defer a.mu.Unlock()
+    a.specs[spec.Force] = spec
+}
+
+// Spec 获取作用力声明
+func (a *Algebra) Spec(f Force) (Spec, bool) {
+    a.mu.RLock()
+    defer a.mu.RUnlock()
+    spec, exists := a.specs[f]
+    return spec, exists
+}
+
+// SetConflict 声明两力冲突及其解决策略
+func (a *Algebra) SetConflict(x, y Force, r Resolution) {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+    a.conflicts[pairOf(x, y)] = r
+}
+
+// ClearConflict 取消两力的冲突声明
+func (a *Algebra) ClearConflict(x, y Force) {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+    delete(a.conflicts, pairOf(x, y))
+}
+
+// SetComposition 声明两力同时作用时的合成规则
+func (a *Algebra) SetComposition(x, y Force, rule ComposeRule) {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+    a.compositions[pairOf(x, y)] = rule
+}
+
+// Conflicts 检查两力是否冲突
+func (a *Algebra) Conflicts(x, y Force) bool {
+    a.mu.RLock()
+    defer a.mu.RUnlock()
+    _, exists := a.conflicts[pairOf(x, y)]
+    return exists
+}
+
+// Validate 校验作用力已注册且强度在范围内
+func (a *Algebra) Validate(v Vector) error {
+    spec, exists := a.Spec(v.Force)
+    if !exists {
+        return fmt.Errorf("%w: %v", ErrUnknownForce, v.Force)
+    }
+    if math.IsNaN(v.Magnitude) || v.Magnitude < MinMagnitude || v.Magnitude > spec.MaxMagnitude {
+        return fmt.Errorf("%w: %s %.2f not in [%.0f, %.0f]", ErrInvalidMagnitude, spec.Name, v.Magnitude, MinMagnitude, spec.MaxMagnitude)
+    }
+    return nil
+}
+
+// Compose 合成同时作用的一组力
+func (a *Algebra) Compose(vs ...Vector) (Vector, error) {
+    if len(vs) == 0 {
+        return Vector{}, ErrEmptyVector
+    }
+
+    for _, v := range vs {
+        if err := a.Validate(v); err != nil {
+            return Vector{}, err
+        }
+    }
+
+    acc := vs[0]
+    for _, v := range vs[1:] {
+        next, err := a.combine(acc, v, true)
+        if err != nil {
+            return Vector{}, err
+        }
+        acc = next
+    }
+    return acc, nil
+}
+
+// Apply 在当前作用力之上施加一组同时作用的力，返回新的作用力
+// 同时作用的力先合成，再与当前作用力按冲突策略结合；不冲突时新力取代当前力
+func (a *Algebra) Apply(current Vector, vs ...Vector) (Vector, error) {
+    incoming, err := a.Compose(vs...)
+    if err != nil {
+        return current, err
+    }
+    if current.IsZero() {
+        return incoming, nil
+    }
+
+    next, err := a.combine(current, incoming, false)
+    if err != nil {
+        return current, err
+    }
+    return next, nil
+}
+
+// combine 结合两力，concurrent 表示同时作用
+func (a *Algebra) combine(x, y Vector, concurrent bool) (Vector, error) {
+    a.mu.RLock()
+    resolution, conflict := a.conflicts[pairOf(x.Force, y.Force)]
+    rule := a.compositions[pairOf(x.Force, y.Force)]
+    a.mu.RUnlock()
+
+    if !conflict {
+        if !concurrent {
+            return y, nil
+        }
+        if rule != nil {
+            return a.clamp(rule(x, y)), nil
+        }
+        return a.clamp(defaultCompose(x, y)), nil
+    }
+
+    switch resolution {
+    case ResolveOverride:
+        return y, nil
+    case ResolveStronger:
+        if x.Magnitude > y.Magnitude {
+            return x, nil
+        }
+        if y.Magnitude > x.Magnitude {
+            return y, nil
+        }
+    case ResolveCancel:
+        if x.Magnitude > y.Magnitude {
+            return a.clamp(Vector{Force: x.Force, Magnitude: x.Magnitude - y.Magnitude}), nil
+        }
+        if y.Magnitude > x.Magnitude {
+            return a.clamp(Vector{Force: y.Force, Magnitude: y.Magnitude - x.Magnitude}), nil
+        }
+        return a.clamp(Vector{Force: Balance, Magnitude: x.Magnitude}), nil
+    case ResolveCompose:
+        if rule != nil {
+            return a.clamp(rule(x, y)), nil
+        }
+        return a.clamp(defaultCompose(x, y)), nil
+    }
+
+    return x, fmt.Errorf("%w: %v and %v", ErrForceConflict, x.Force, y.Force)
+}
+
+// defaultCompose 默认合成：同种力强度相加，异种力取强者并叠加强度
+func defaultCompose(x, y Vector) Vector {
+    if x.Force == y.Force || x.Magnitude >= y.Magnitude {
+        return Vector{Force: x.Force, Magnitude: x.Magnitude + y.Magnitude}
+    }
+    return Vector{Force: y.Force, Magnitude: x.Magnitude + y.Magnitude}
+}
+
+// clamp 将强度限制在作用力声明的范围内
+func (a *Algebra) clamp(v Vector) Vector {
+    limit := MaxMagnitude
+    if spec, exists := a.Spec(v.Force); exists {
+        limit = spec.MaxMagnitude
+    }
+    if v.Magnitude > limit {
+        v.Magnitude = limit
+    }
+    if v.Magnitude < MinMagnitude {
+        v.Magnitude = MinMagnitude
+    }
+    return v
+}
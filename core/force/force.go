@@ -1,18 +1,62 @@
 package force
 
+import (
+    "fmt"
+)
+
 type Force uint8
 
 const (
-    Create  Force = iota // 生之力
-    Destroy             // 灭之力
-    Transform           // 变之力
-    Balance            // 衡之力
+    None      Force = iota // 无力
+    Create                 // 生之力
+    Destroy                // 灭之力
+    Transform              // 变之力
+    Balance                // 衡之力
+)
+
+// FirstCustom 自定义作用力的起始取值，之前的取值保留给内置作用力
+const FirstCustom Force = 16
+
+// 作用力强度范围
+const (
+    MaxMagnitude = 100.0
+    MinMagnitude = 1.0
 )
 
 // ForceInteraction 定义力之间的相互作用规则
+// 列表中的力可与之共存，内置力之间不在列表中的组合视为冲突
 var ForceInteraction = map[Force][]Force{
     Create:    {Transform, Balance},
     Destroy:   {Transform, Balance},
     Transform: {Create, Destroy, Balance},
     Balance:   {Create, Destroy, Transform},
 }
+
+// String 获取内置作用力名称
+func (f Force) String() string {
+    switch f {
+    case None:
+        return "None"
+    case Create:
+        return "Create"
+    case Destroy:
+        return "Destroy"
+    case Transform:
+        return "Transform"
+    case Balance:
+        return "Balance"
+    default:
+        return fmt.Sprintf("Force(%d)", uint8(f))
+    }
+}
+
+// Vector 带强度的作用力
+type Vector struct {
+    Force     Force
+    Magnitude float64 // MinMagnitude - MaxMagnitude
+}
+
+// IsZero 检查是否为空作用力
+func (v Vector) IsZero() bool {
+    return v.Force == None
+}
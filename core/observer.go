@@ -0,0 +1,331 @@
+// core/observer.go
+
+package core
+
+import (
+    "context"
+    "sync"
+    "time"
+)
+
+// 观察者相关定义
+type EventType uint8
+
+const (
+    EventNewElement EventType = iota
+    EventPhaseChange
+    EventInteraction
+    EventElementRemoved
+)
+
+// String 获取事件类型名称
+func (t EventType) String() string {
+    switch t {
+    case EventNewElement:
+        return "new-element"
+    case EventPhaseChange:
+        return "phase-change"
+    case EventInteraction:
+        return "interaction"
+    case EventElementRemoved:
+        return "element-removed"
+    default:
+        return "unknown"
+    }
+}
+
+// UniverseEvent 宇宙事件
+type UniverseEvent interface {
+    Type() EventType
+    At() time.Time
+}
+
+// NewElementEvent 万物生成新元素
+type NewElementEvent struct {
+    Element *Element
+    Time    time.Time
+}
+
+// PhaseChangeEvent 演化阶段变化
+type PhaseChangeEvent struct {
+    From Phase
+    To   Phase
+    Time time.Time
+}
+
+// InteractionEvent 五行相互作用
+type InteractionEvent struct {
+    Mother *Element // 相生之母
+    Child  *Element // 相生之子
+    Result *Element // 生成的元素
+    Time   time.Time
+}
+
+// ElementRemovedEvent 元素被种群管理移除
+type ElementRemovedEvent struct {
+    Element *Element
+    Reason  RemovalReason
+    Into    *Element // 合并时为吸收该元素的元素
+    Time    time.Time
+}
+
+func (e NewElementEvent) Type() EventType     { return EventNewElement }
+func (e NewElementEvent) At() time.Time       { return e.Time }
+func (e PhaseChangeEvent) Type() EventType    { return EventPhaseChange }
+func (e PhaseChangeEvent) At() time.Time      { return e.Time }
+func (e InteractionEvent) Type() EventType    { return EventInteraction }
+func (e InteractionEvent) At() time.Time      { return e.Time }
+func (e ElementRemovedEvent) Type() EventType { return EventElementRemoved }
+func (e ElementRemovedEvent) At() time.Time   { return e.Time }
+
+// Observer 宇宙观察者，data 为对应的事件结构体
+type Observer interface {
+    OnEvent(eventType EventType, data interface{})
+}
+
+// OverflowPolicy 订阅队列已满时的处理策略
+type OverflowPolicy uint8
+
+const (
+    OverflowDrop     OverflowPolicy = iota // 丢弃新事件
+    OverflowBlock                          // 阻塞发布方直至队列有空位
+    OverflowCoalesce                       // 以新事件替换队列中同类型的最新事件，无同类型事件时丢弃最旧事件
+)
+
+// DefaultQueueSize 订阅队列的默认容量
+const DefaultQueueSize = 64
+
+// SubscribeOption 订阅选项
+type SubscribeOption func(*Subscription)
+
+// WithQueueSize 设置订阅队列容量
+func WithQueueSize(size int) SubscribeOption {
+    return func(s *Subscription) {
+        if size > 0 {
+            s.capacity = size
+        }
+    }
+}
+
+// WithOverflow 设置队列溢出策略
+func WithOverflow(policy OverflowPolicy) SubscribeOption {
+    return func(s *Subscription) {
+        s.policy = policy
+    }
+}
+
+// WithEventTypes 只接收指定类型的事件
+func WithEventTypes(types ...EventType) SubscribeOption {
+    return func(s *Subscription) {
+        s.filter = make(map[EventType]bool, len(types))
+        for _, t := range types {
+            s.filter[t] = true
+        }
+    }
+}
+
+// Subscription 宇宙事件订阅，每个订阅拥有独立的有界队列与投递协程
+type Subscription struct {
+    universe *Universe
+    handler  func(UniverseEvent)
+    filter   map[EventType]bool // 为 nil 时接收全部事件
+    policy   OverflowPolicy
+    capacity int
+
+    mu         sync.Mutex
+    notEmpty   *sync.Cond
+    notFull    *sync.Cond
+    idle       *sync.Cond
+    queue      []UniverseEvent
+    delivering bool
+    closed     bool
+    dropped    uint64
+}
+
+// Subscribe 订阅宇宙事件，handler 在独立协程中按顺序调用
+// handler 为 nil 时 panic；宇宙停止后订阅的返回已关闭的订阅
+func (u *Universe) Subscribe(handler func(UniverseEvent), opts ...SubscribeOption) *Subscription {
+    if handler == nil {
+        panic("core: Subscribe with nil handler")
+    }
+    s := &Subscription{
+        universe: u,
+        handler:  handler,
+        policy:   OverflowDrop,
+        capacity: DefaultQueueSize,
+    }
+    for _, opt := range opts {
+        opt(s)
+    }
+    s.queue = make([]UniverseEvent, 0, s.capacity)
+    s.notEmpty = sync.NewCond(&s.mu)
+    s.notFull = sync.NewCond(&s.mu)
+    s.idle = sync.NewCond(&s.mu)
+
+    u.mu.Lock()
+    select {
+    case <-u.done:
+        u.mu.Unlock()
+        s.close()
+        return s
+    default:
+    }
+    u.subscribers = append(u.subscribers, s)
+    u.mu.Unlock()
+
+    go s.run()
+    return s
+}
+
+// AddObserver 以默认选项订阅全部事件
+func (u *Universe) AddObserver(observer Observer, opts ...SubscribeOption) *Subscription {
+    return u.Subscribe(func(e UniverseEvent) {
+        observer.OnEvent(e.Type(), e)
+    }, opts...)
+}
+
+// Unsubscribe 取消订阅，队列中尚未投递的事件被丢弃
+func (s *Subscription) Unsubscribe() {
+    u := s.universe
+    u.mu.Lock()
+    for i, x := range u.subscribers {
+        if x == s {
+            u.subscribers = append(u.subscribers[:i], u.subscribers[i+1:]...)
+            break
+        }
+    }
+    u.mu.Unlock()
+
+    s.close()
+}
+
+// close 关闭订阅并唤醒投递协程与等待者，队列中的事件被丢弃
+func (s *Subscription) close() {
+    s.mu.Lock()
+    s.closed = true
+    s.queue = s.queue[:0]
+    s.notEmpty.Broadcast()
+    s.notFull.Broadcast()
+    s.idle.Broadcast()
+    s.mu.Unlock()
+}
+
+// Dropped 获取因队列溢出而丢弃的事件数
+func (s *Subscription) Dropped() uint64 {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return s.dropped
+}
+
+// Flush 等待队列中的事件投递完毕
+func (s *Subscription) Flush(ctx context.Context) error {
+    stop := context.AfterFunc(ctx, func() {
+        s.mu.Lock()
+        s.idle.Broadcast()
+        s.mu.Unlock()
+    })
+    defer stop()
+
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    for !s.closed && (len(s.queue) > 0 || s.delivering) {
+        if err := ctx.Err(); err != nil {
+            return err
+        }
+        s.idle.Wait()
+    }
+    return nil
+}
+
+// publish 将事件放入队列
+func (s *Subscription) publish(e UniverseEvent) {
+    if s.filter != nil && !s.filter[e.Type()] {
+        return
+    }
+
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for !s.closed && len(s.queue) >= s.capacity {
+        switch s.policy {
+        case OverflowBlock:
+            s.notFull.Wait()
+            continue
+        case OverflowCoalesce:
+            s.dropped++
+            for i := len(s.queue) - 1; i >= 0; i-- {
+                if s.queue[i].Type() == e.Type() {
+                    s.queue[i] = e
+                    return
+                }
+            }
+            s.queue = append(s.queue[:0], s.queue[1:]...)
+        default:
+            s.dropped++
+            return
+        }
+    }
+    if s.closed {
+        return
+    }
+    s.queue = append(s.queue, e)
+    s.notEmpty.Signal()
+}
+
+// run 按顺序投递事件
+func (s *Subscription) run() {
+    for {
+        s.mu.Lock()
+        for !s.closed && len(s.queue) == 0 {
+            s.notEmpty.Wait()
+        }
+        if s.closed {
+            s.mu.Unlock()
+            return
+        }
+        e := s.queue[0]
+        s.queue = append(s.queue[:0], s.queue[1:]...)
+        s.delivering = true
+        s.notFull.Signal()
+        s.mu.Unlock()
+
+        s.handler(e)
+
+        s.mu.Lock()
+        s.delivering = false
+        if len(s.queue) == 0 {
+            s.idle.Broadcast()
+        }
+        s.mu.Unlock()
+    }
+}
+
+// Flush 等待全部订阅的事件投递完毕，可注册为 TaiJi 的 Flusher
+func (u *Universe) Flush(ctx context.Context) error {
+    u.mu.RLock()
+    subs := append([]*Subscription(nil), u.subscribers...)
+    u.mu.RUnlock()
+
+    for _, s := range subs {
+        if err := s.Flush(ctx); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// publish 在不持有 u.mu 的情况下向订阅者发布事件
+func (u *Universe) publish(events []UniverseEvent) {
+    if len(events) == 0 {
+        return
+    }
+    u.mu.RLock()
+    subs := append([]*Subscription(nil), u.subscribers...)
+    u.mu.RUnlock()
+
+    for _, e := range events {
+        for _, s := range subs {
+            s.publish(e)
+        }
+    }
+}
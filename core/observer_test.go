@@ -0,0 +1,175 @@
+// core/observer_test.go
+
+package core
+
+import (
+    "context"
+    "reflect"
+    "sync"
+    "testing"
+    "time"
+)
+
+func TestElementEventsExposeElementValues(t *testing.T) {
+    sim, err := NewSimulation(context.Background(), SimulationConfig{Seed: 5})
+    if err != nil {
+        t.Fatal(err)
+    }
+    u := sim.Universe()
+
+    events := make(chan NewElementEvent, 64)
+    sub := u.Subscribe(func(e UniverseEvent) {
+        if created, ok := e.(NewElementEvent); ok {
+            select {
+            case events <- created:
+            default:
+            }
+        }
+    }, WithEventTypes(EventNewElement))
+    defer sub.Unsubscribe()
+
+    for i := 0; i < 50 && len(events) == 0; i++ {
+        sim.Step(1)
+        if err := sub.Flush(context.Background()); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if len(events) == 0 {
+        t.Fatal("no element generated")
+    }
+
+    e := (<-events).Element
+    if e.Phase() != UniverseWanWu {
+        t.Errorf("phase = %v, want WanWu", e.Phase())
+    }
+    if sum := e.Yin() + e.Yang(); sum < 0.999 || sum > 1.001 {
+        t.Errorf("yin + yang = %v, want 1", sum)
+    }
+    if e.Energy() <= 0 {
+        t.Errorf("energy = %v, want > 0", e.Energy())
+    }
+}
+
+// blockedSubscriber 容量为 2 的订阅，投递首个事件后阻塞直至 release 关闭
+type blockedSubscriber struct {
+    universe *Universe
+    sub      *Subscription
+    started  chan struct{}
+    release  chan struct{}
+
+    mu       sync.Mutex
+    received []UniverseEvent
+}
+
+func newBlockedSubscriber(t *testing.T, policy OverflowPolicy) *blockedSubscriber {
+    t.Helper()
+    b := &blockedSubscriber{
+        universe: NewUniverse(context.Background(), WithSeed(1)),
+        started:  make(chan struct{}),
+        release:  make(chan struct{}),
+    }
+    first := true
+    b.sub = b.universe.Subscribe(func(e UniverseEvent) {
+        if first {
+            first = false
+            close(b.started)
+            <-b.release
+        }
+        b.mu.Lock()
+        b.received = append(b.received, e)
+        b.mu.Unlock()
+    }, WithQueueSize(2), WithOverflow(policy))
+    t.Cleanup(b.universe.Stop)
+
+    b.publish(phaseEvent(0))
+    <-b.started
+    return b
+}
+
+func (b *blockedSubscriber) publish(events ...UniverseEvent) {
+    b.universe.publish(events)
+}
+
+// drain 放行并等待投递完毕，返回收到的事件
+func (b *blockedSubscriber) drain(t *testing.T) []UniverseEvent {
+    t.Helper()
+    close(b.release)
+    if err := b.sub.Flush(context.Background()); err != nil {
+        t.Fatal(err)
+    }
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    return append([]UniverseEvent(nil), b.received...)
+}
+
+// phaseEvent 以 To 标记的阶段事件
+func phaseEvent(mark Phase) UniverseEvent {
+    return PhaseChangeEvent{To: mark}
+}
+
+func TestOverflowDropDiscardsNewEvents(t *testing.T) {
+    b := newBlockedSubscriber(t, OverflowDrop)
+    b.publish(phaseEvent(1), phaseEvent(2), phaseEvent(3))
+
+    got := b.drain(t)
+    want := []UniverseEvent{phaseEvent(0), phaseEvent(1), phaseEvent(2)}
+    if !reflect.DeepEqual(got, want) {
+        t.Fatalf("received %v, want %v", got, want)
+    }
+    if d := b.sub.Dropped(); d != 1 {
+        t.Fatalf("dropped = %d, want 1", d)
+    }
+}
+
+func TestOverflowBlockWaitsForRoom(t *testing.T) {
+    b := newBlockedSubscriber(t, OverflowBlock)
+    b.publish(phaseEvent(1), phaseEvent(2))
+
+    published := make(chan struct{})
+    go func() {
+        b.publish(phaseEvent(3))
+        close(published)
+    }()
+    select {
+    case <-published:
+        t.Fatal("publish returned while the queue was full")
+    case <-time.After(20 * time.Millisecond):
+    }
+
+    b.drain(t)
+    <-published
+    if err := b.sub.Flush(context.Background()); err != nil {
+        t.Fatal(err)
+    }
+    b.mu.Lock()
+    got := append([]UniverseEvent(nil), b.received...)
+    b.mu.Unlock()
+
+    want := []UniverseEvent{phaseEvent(0), phaseEvent(1), phaseEvent(2), phaseEvent(3)}
+    if !reflect.DeepEqual(got, want) {
+        t.Fatalf("received %v, want %v", got, want)
+    }
+    if d := b.sub.Dropped(); d != 0 {
+        t.Fatalf("dropped = %d, want 0", d)
+    }
+}
+
+func TestOverflowCoalesceReplacesSameType(t *testing.T) {
+    b := newBlockedSubscriber(t, OverflowCoalesce)
+    interaction := InteractionEvent{}
+    removed := ElementRemovedEvent{Reason: RemovedDepleted}
+    b.publish(phaseEvent(1), interaction)
+
+    // 同类型事件替换队列中的阶段事件；无同类型时丢弃最旧的事件
+    b.publish(phaseEvent(2))
+    b.publish(removed)
+
+    got := b.drain(t)
+    want := []UniverseEvent{phaseEvent(0), interaction, removed}
+    if !reflect.DeepEqual(got, want) {
+        t.Fatalf("received %v, want %v", got, want)
+    }
+    if d := b.sub.Dropped(); d != 2 {
+        t.Fatalf("dropped = %d, want 2", d)
+    }
+}
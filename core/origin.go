@@ -4,6 +4,10 @@ package core
 
 import (
     "context"
+    "sync"
+    "time"
+
+    "github.com/Corphon/daoframe/core/clock"
     "github.com/Corphon/daoframe/core/state"  // 新的导入
     "github.com/Corphon/daoframe/core/force"  // 新的导入
 )
@@ -13,7 +17,8 @@ type Origin struct {
     essence *DaoContext    // 道之精髓
     energy  *AdaptSystem   // 道之能量
     form    *BaseDaoSource // 道之形态
-    components []Component
+    components *ComponentRegistry // 有序组件
+    flushers   []Flusher          // 关闭时刷新的观察者
     mu         sync.RWMutex
     done       chan struct{}
 }
@@ -25,7 +30,11 @@ type Component interface {
 // 太极 - 表示最初的统一状态
 type TaiJi struct {
     origin *Origin
+    mu     sync.Mutex  // 保护 state，Generate 与 Shutdown 互斥
     state  state.State
+
+    shutdownOnce sync.Once
+    shutdownErr  error
 }
 
 // 创建太极，实现"道生一"
@@ -34,6 +43,8 @@ func NewTaiJi() *TaiJi {
         essence: NewDaoContext(context.Background()),
         energy:  NewAdaptSystem(DefaultInterval),
         form:    NewBaseDaoSource(),
+        components: NewComponentRegistry(),
+        done:       make(chan struct{}),
     }
     
     return &TaiJi{
@@ -42,8 +53,50 @@ func NewTaiJi() *TaiJi {
     }
 }
 
+// RegisterComponent 注册组件，组件在 Generate 时按依赖顺序启动
+func (t *TaiJi) RegisterComponent(name string, c Component, opts ...ComponentOption) error {
+    return t.origin.components.Register(name, c, opts...)
+}
+
+// Components 获取组件注册表
+func (t *TaiJi) Components() *ComponentRegistry {
+    return t.origin.components
+}
+
+// SetClock 设置时钟，应在 Generate 之前调用
+// 时钟同时交给能量系统并写入本源上下文，以 Context 构造的模型经 clock.FromContext 使用同一时钟
+func (t *TaiJi) SetClock(c clock.Clock) {
+    t.origin.energy.SetClock(c)
+    t.origin.essence.wrap(func(ctx context.Context) context.Context {
+        return clock.NewContext(ctx, c)
+    })
+}
+
+// SetSchedule 设置判断阴阳主导的昼夜时刻表，同样写入本源上下文
+func (t *TaiJi) SetSchedule(s clock.Schedule) {
+    t.origin.energy.SetSchedule(s)
+    t.origin.essence.wrap(func(ctx context.Context) context.Context {
+        return clock.NewScheduleContext(ctx, s)
+    })
+}
+
+// Context 获取本源上下文，携带 SetClock 与 SetSchedule 设置的时钟与时刻表
+func (t *TaiJi) Context() *DaoContext {
+    return t.origin.essence
+}
+
+// State 获取太极当前状态
+func (t *TaiJi) State() state.State {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    return t.state
+}
+
 // Generate 生成万物的起点，对应"道生一"
 func (t *TaiJi) Generate() (*YinYang, error) {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+
     if t.state != state.StateInactive {
         return nil, ErrInvalidState
     }
@@ -57,16 +110,22 @@ func (t *TaiJi) Generate() (*YinYang, error) {
     if err := t.origin.energy.Start(t.origin.essence); err != nil {
         return nil, err
     }
+
+    // 按依赖顺序启动组件，失败时已启动组件自动回滚
+    if err := t.origin.components.Start(t.origin.essence); err != nil {
+        t.origin.energy.Stop()
+        return nil, err
+    }
     
     t.state = state.StateActive
     
     // 返回阴阳二气，为"一生二"做准备
-    return NewYinYang(t.origin), nil
+    return NewYinYang(t), nil
 }
 
 // 宇宙常数
 const (
     DefaultInterval = time.Second * 1 // 基本时间单位
-    MaximumForce   = 100             // 最大作用力
-    MinimumForce   = 1               // 最小作用力
+    MaximumForce   = force.MaxMagnitude // 最大作用力
+    MinimumForce   = force.MinMagnitude // 最小作用力
 )
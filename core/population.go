@@ -0,0 +1,150 @@
+// core/population.go
+
+package core
+
+import (
+    "sort"
+)
+
+// RemovalReason 元素被移除的原因
+type RemovalReason uint8
+
+const (
+    RemovedDepleted RemovalReason = iota // 能量耗尽
+    RemovedMerged                        // 并入相似元素
+    RemovedEvicted                       // 超出容量被淘汰
+)
+
+// String 获取原因名称
+func (r RemovalReason) String() string {
+    switch r {
+    case RemovedDepleted:
+        return "depleted"
+    case RemovedMerged:
+        return "merged"
+    case RemovedEvicted:
+        return "evicted"
+    default:
+        return "unknown"
+    }
+}
+
+// PopulationConfig 万物种群管理配置
+// 只管理万物阶段的元素，太极、阴阳、三态与五行元素不受影响
+type PopulationConfig struct {
+    Capacity       int     // 万物元素上限，为 0 时不限制
+    DecayRate      float64 // 每步能量衰减比例 (0-1)
+    MinEnergy      float64 // 能量低于此值的元素被移除
+    MergeTolerance float64 // 阴性比例相差不超过此值的元素合并，为 0 时不合并
+}
+
+// DefaultPopulationConfig 默认种群配置
+func DefaultPopulationConfig() PopulationConfig {
+    return PopulationConfig{
+        Capacity:       10000,
+        DecayRate:      0.01,
+        MinEnergy:      0.0001,
+        MergeTolerance: 0.001,
+    }
+}
+
+// WithPopulation 设置种群管理配置
+func WithPopulation(config PopulationConfig) UniverseOption {
+    return func(u *Universe) {
+        u.population = config
+    }
+}
+
+// maintainPopulation 对万物元素执行衰减、移除耗尽元素、合并相似元素与容量淘汰
+// 返回被移除的元素，调用前须持有锁
+func (u *Universe) maintainPopulation() []ElementRemovedEvent {
+    cfg := u.population
+    removals := make([]ElementRemovedEvent, 0)
+    removed := make(map[*Element]bool)
+
+    // 衰减并移除耗尽的元素，在元素锁内记录阴性比例与能量供后续排序
+    alive := make([]elementSample, 0)
+    for _, e := range u.elements {
+        if e.phase != UniverseWanWu {
+            continue
+        }
+        e.mu.Lock()
+        e.energy *= 1 - cfg.DecayRate
+        sample := elementSample{element: e, yin: e.yin, energy: e.energy}
+        e.mu.Unlock()
+
+        if sample.energy < cfg.MinEnergy {
+            removed[e] = true
+            removals = append(removals, ElementRemovedEvent{Element: e, Reason: RemovedDepleted})
+        } else {
+            alive = append(alive, sample)
+        }
+    }
+
+    // 按阴性比例排序后合并相邻的相似元素，吸收方按能量加权阴阳比例
+    if cfg.MergeTolerance > 0 && len(alive) > 1 {
+        sort.SliceStable(alive, func(i, j int) bool {
+            return alive[i].yin < alive[j].yin
+        })
+        merged := alive[:1]
+        for _, sample := range alive[1:] {
+            into := &merged[len(merged)-1]
+            if sample.yin-into.yin > cfg.MergeTolerance {
+                merged = append(merged, sample)
+                continue
+            }
+            into.yin, into.energy = into.element.absorb(sample.element)
+            removed[sample.element] = true
+            removals = append(removals, ElementRemovedEvent{Element: sample.element, Reason: RemovedMerged, Into: into.element})
+        }
+        alive = merged
+    }
+
+    // 超出容量时淘汰能量最低的元素
+    if cfg.Capacity > 0 && len(alive) > cfg.Capacity {
+        sort.SliceStable(alive, func(i, j int) bool {
+            return alive[i].energy < alive[j].energy
+        })
+        for _, sample := range alive[:len(alive)-cfg.Capacity] {
+            removed[sample.element] = true
+            removals = append(removals, ElementRemovedEvent{Element: sample.element, Reason: RemovedEvicted})
+        }
+    }
+
+    if len(removed) > 0 {
+        kept := u.elements[:0]
+        for _, e := range u.elements {
+            if !removed[e] {
+                kept = append(kept, e)
+            }
+        }
+        for i := len(kept); i < len(u.elements); i++ {
+            u.elements[i] = nil
+        }
+        u.elements = kept
+    }
+    return removals
+}
+
+// elementSample 种群维护时在元素锁内记录的阴性比例与能量
+type elementSample struct {
+    element *Element
+    yin     float64
+    energy  float64
+}
+
+// absorb 吸收另一元素的能量，阴阳比例按能量加权，返回吸收后的阴性比例与能量
+func (e *Element) absorb(other *Element) (float64, float64) {
+    e.mu.Lock()
+    defer e.mu.Unlock()
+    other.mu.RLock()
+    defer other.mu.RUnlock()
+
+    total := e.energy + other.energy
+    if total > 0 {
+        e.yin = (e.yin*e.energy + other.yin*other.energy) / total
+        e.yang = 1 - e.yin
+    }
+    e.energy = total
+    return e.yin, e.energy
+}
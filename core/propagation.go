@@ -0,0 +1,291 @@
+// core/propagation.go
+
+package core
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/base64"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// ErrMalformedCarrier 载体中的上下文字段无法解析
+var ErrMalformedCarrier = errors.New("malformed dao context carrier")
+
+// 传播字段名，加在 TextMapPropagator.Prefix 之后
+const (
+    FieldPhase     = "Phase"
+    FieldAttribute = "Attribute"
+    FieldDeadline  = "Deadline"
+    FieldTraceID   = "Trace-Id"
+    FieldValues    = "Values"
+)
+
+// DefaultPropagationPrefix 默认字段前缀
+const DefaultPropagationPrefix = "Dao-"
+
+// 入站载体的默认限制
+const (
+    DefaultMaxValuesSize = 64 << 10   // 传播值解码后的最大字节数
+    DefaultMaxTimeout    = time.Minute // 入站截止时间距当前的最大时长
+    MaxTraceIDLength     = 128         // 追踪标识的最大长度
+)
+
+// TraceIDKey 追踪标识，由传播器单独编码
+var TraceIDKey = NewKey[string]("dao.trace-id")
+
+// TextMapCarrier 文本键值载体，供各类传输复用
+type TextMapCarrier interface {
+    Get(key string) string
+    Set(key, value string)
+    Keys() []string
+}
+
+// MapCarrier 基于 map 的载体
+type MapCarrier map[string]string
+
+func (c MapCarrier) Get(key string) string { return c[key] }
+func (c MapCarrier) Set(key, value string) { c[key] = value }
+
+func (c MapCarrier) Keys() []string {
+    keys := make([]string, 0, len(c))
+    for k := range c {
+        keys = append(keys, k)
+    }
+    return keys
+}
+
+// HeaderCarrier 基于 http.Header 的载体
+type HeaderCarrier http.Header
+
+func (c HeaderCarrier) Get(key string) string { return http.Header(c).Get(key) }
+func (c HeaderCarrier) Set(key, value string) { http.Header(c).Set(key, value) }
+
+func (c HeaderCarrier) Keys() []string {
+    keys := make([]string, 0, len(c))
+    for k := range c {
+        keys = append(keys, k)
+    }
+    return keys
+}
+
+// MetadataCarrier gRPC 风格的元数据载体，键统一为小写
+type MetadataCarrier map[string][]string
+
+func (c MetadataCarrier) Get(key string) string {
+    if values := c[strings.ToLower(key)]; len(values) > 0 {
+        return values[0]
+    }
+    return ""
+}
+
+func (c MetadataCarrier) Set(key, value string) {
+    c[strings.ToLower(key)] = []string{value}
+}
+
+func (c MetadataCarrier) Keys() []string {
+    keys := make([]string, 0, len(c))
+    for k := range c {
+        keys = append(keys, k)
+    }
+    return keys
+}
+
+// TextMapPropagator 将 DaoContext 编码到文本键值载体
+// 传播阶段、阴阳属性、截止时间、追踪标识与传播键的值
+type TextMapPropagator struct {
+    Prefix        string
+    MaxValuesSize int           // 为 0 时使用 DefaultMaxValuesSize
+    MaxTimeout    time.Duration // 为 0 时使用 DefaultMaxTimeout
+}
+
+// DefaultPropagator 默认传播器
+var DefaultPropagator = TextMapPropagator{Prefix: DefaultPropagationPrefix}
+
+// field 带前缀的字段名
+func (p TextMapPropagator) field(name string) string {
+    return p.Prefix + name
+}
+
+// maxValuesSize 传播值的大小上限
+func (p TextMapPropagator) maxValuesSize() int {
+    if p.MaxValuesSize > 0 {
+        return p.MaxValuesSize
+    }
+    return DefaultMaxValuesSize
+}
+
+// maxTimeout 入站截止时间的上限
+func (p TextMapPropagator) maxTimeout() time.Duration {
+    if p.MaxTimeout > 0 {
+        return p.MaxTimeout
+    }
+    return DefaultMaxTimeout
+}
+
+// Inject 将上下文写入载体
+func (p TextMapPropagator) Inject(dc *DaoContext, carrier TextMapCarrier) error {
+    attr := dc.GetAttribute()
+    carrier.Set(p.field(FieldPhase), strconv.Itoa(int(dc.GetPhase())))
+    carrier.Set(p.field(FieldAttribute), fmt.Sprintf("%d,%d", attr.Yin, attr.Yang))
+
+    if deadline, ok := dc.Deadline(); ok {
+        carrier.Set(p.field(FieldDeadline), deadline.UTC().Format(time.RFC3339Nano))
+    }
+    if traceID, ok := TraceIDKey.Get(dc); ok && traceID != "" {
+        carrier.Set(p.field(FieldTraceID), traceID)
+    }
+
+    values, err := dc.PropagatingValues()
+    if err != nil {
+        return err
+    }
+    if len(values) > 0 {
+        data, err := json.Marshal(values)
+        if err != nil {
+            return err
+        }
+        carrier.Set(p.field(FieldValues), base64.RawURLEncoding.EncodeToString(data))
+    }
+    return nil
+}
+
+// Extract 基于 ctx 从载体还原上下文
+// 载体带有截止时间时返回的上下文受其约束，调用方须调用 cancel 释放资源；
+// 截止时间不晚于当前时间加 MaxTimeout，不合法的追踪标识被丢弃
+func (p TextMapPropagator) Extract(ctx context.Context, carrier TextMapCarrier) (*DaoContext, context.CancelFunc, error) {
+    if ctx == nil {
+        ctx = context.Background()
+    }
+
+    cancel := context.CancelFunc(func() {})
+    if raw := carrier.Get(p.field(FieldDeadline)); raw != "" {
+        deadline, err := time.Parse(time.RFC3339Nano, raw)
+        if err != nil {
+            return nil, cancel, fmt.Errorf("%w: deadline %q", ErrMalformedCarrier, raw)
+        }
+        if limit := time.Now().Add(p.maxTimeout()); deadline.After(limit) {
+            deadline = limit
+        }
+        ctx, cancel = context.WithDeadline(ctx, deadline)
+    }
+
+    dc := NewDaoContext(ctx)
+    dc.cancel = cancel
+    if err := p.extractInto(dc, carrier); err != nil {
+        cancel()
+        return nil, func() {}, err
+    }
+    return dc, cancel, nil
+}
+
+// extractInto 将载体中除截止时间外的字段写入上下文
+func (p TextMapPropagator) extractInto(dc *DaoContext, carrier TextMapCarrier) error {
+    if raw := carrier.Get(p.field(FieldPhase)); raw != "" {
+        phase, err := strconv.Atoi(raw)
+        if err != nil || phase < int(PhaseWuJi) || phase > int(PhaseWanWu) {
+            return fmt.Errorf("%w: phase %q", ErrMalformedCarrier, raw)
+        }
+        dc.SetPhase(DaoPhase(phase))
+    }
+
+    if raw := carrier.Get(p.field(FieldAttribute)); raw != "" {
+        attr, err := parseAttribute(raw)
+        if err != nil {
+            return err
+        }
+        dc.mu.Lock()
+        dc.attributes = &attr
+        dc.mu.Unlock()
+    }
+
+    if raw := carrier.Get(p.field(FieldValues)); raw != "" {
+        if len(raw) > base64.RawURLEncoding.EncodedLen(p.maxValuesSize()) {
+            return fmt.Errorf("%w: values exceed %d bytes", ErrMalformedCarrier, p.maxValuesSize())
+        }
+        data, err := base64.RawURLEncoding.DecodeString(raw)
+        if err != nil {
+            return fmt.Errorf("%w: values: %v", ErrMalformedCarrier, err)
+        }
+        var values map[string]json.RawMessage
+        if err := json.Unmarshal(data, &values); err != nil {
+            return fmt.Errorf("%w: values: %v", ErrMalformedCarrier, err)
+        }
+        for name := range values {
+            if reservedKeys[name] {
+                return fmt.Errorf("%w: reserved value %q", ErrMalformedCarrier, name)
+            }
+        }
+        dc.SetPropagatingValues(values)
+    }
+
+    // 追踪标识最后写入，经校验的值不会被传播值覆盖
+    if traceID := carrier.Get(p.field(FieldTraceID)); validTraceID(traceID) {
+        TraceIDKey.Set(dc, traceID)
+    }
+    return nil
+}
+
+// reservedKeys 由传播器单独编码的键，不接受经传播值传入
+var reservedKeys = map[string]bool{
+    TraceIDKey.Name(): true,
+}
+
+// validTraceID 追踪标识是否合法：非空、不超过 MaxTraceIDLength，仅含字母、数字与连字符
+func validTraceID(traceID string) bool {
+    if traceID == "" || len(traceID) > MaxTraceIDLength {
+        return false
+    }
+    for i := 0; i < len(traceID); i++ {
+        c := traceID[i]
+        if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+            return false
+        }
+    }
+    return true
+}
+
+// parseAttribute 解析 "yin,yang" 格式的阴阳属性
+func parseAttribute(raw string) (DaoAttribute, error) {
+    parts := strings.Split(raw, ",")
+    if len(parts) != 2 {
+        return DaoAttribute{}, fmt.Errorf("%w: attribute %q", ErrMalformedCarrier, raw)
+    }
+    yin, err1 := strconv.ParseUint(strings.TrimSpace(parts[0]), 10, 8)
+    yang, err2 := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 8)
+    if err1 != nil || err2 != nil || yin > 100 || yang > 100 {
+        return DaoAttribute{}, fmt.Errorf("%w: attribute %q", ErrMalformedCarrier, raw)
+    }
+    return DaoAttribute{Yin: uint8(yin), Yang: uint8(yang)}, nil
+}
+
+// EnsureTraceID 获取追踪标识，不存在时生成新的标识
+func EnsureTraceID(dc *DaoContext) string {
+    if traceID, ok := TraceIDKey.Get(dc); ok && traceID != "" {
+        return traceID
+    }
+    buf := make([]byte, 16)
+    if _, err := rand.Read(buf); err != nil {
+        buf = []byte(strconv.FormatInt(time.Now().UnixNano(), 16))
+    }
+    traceID := hex.EncodeToString(buf)
+    TraceIDKey.Set(dc, traceID)
+    return traceID
+}
+
+// InjectHTTP 将上下文写入请求头
+func InjectHTTP(dc *DaoContext, req *http.Request) error {
+    return DefaultPropagator.Inject(dc, HeaderCarrier(req.Header))
+}
+
+// ExtractHTTP 从请求头还原上下文
+func ExtractHTTP(req *http.Request) (*DaoContext, context.CancelFunc, error) {
+    return DefaultPropagator.Extract(req.Context(), HeaderCarrier(req.Header))
+}
@@ -0,0 +1,46 @@
+// core/propagation_test.go
+
+package core
+
+import (
+    "context"
+    "encoding/base64"
+    "errors"
+    "strings"
+    "testing"
+)
+
+func TestExtractRejectsInvalidTraceID(t *testing.T) {
+    carrier := MapCarrier{DefaultPropagationPrefix + FieldTraceID: "bad id\r\n<script>"}
+    dc, cancel, err := DefaultPropagator.Extract(context.Background(), carrier)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer cancel()
+
+    if traceID, ok := TraceIDKey.Get(dc); ok {
+        t.Fatalf("trace id = %q, want none", traceID)
+    }
+    if traceID := EnsureTraceID(dc); !validTraceID(traceID) {
+        t.Fatalf("generated trace id %q is invalid", traceID)
+    }
+}
+
+func TestExtractRejectsTraceIDInValues(t *testing.T) {
+    values := base64.RawURLEncoding.EncodeToString([]byte(`{"dao.trace-id":"bad id\r\n<script>"}`))
+    carrier := MapCarrier{
+        DefaultPropagationPrefix + FieldTraceID: "abc-123",
+        DefaultPropagationPrefix + FieldValues:  values,
+    }
+    if _, _, err := DefaultPropagator.Extract(context.Background(), carrier); !errors.Is(err, ErrMalformedCarrier) {
+        t.Fatalf("err = %v, want ErrMalformedCarrier", err)
+    }
+}
+
+func TestExtractRejectsOversizedValues(t *testing.T) {
+    p := TextMapPropagator{Prefix: DefaultPropagationPrefix, MaxValuesSize: 16}
+    carrier := MapCarrier{DefaultPropagationPrefix + FieldValues: strings.Repeat("A", 64)}
+    if _, _, err := p.Extract(context.Background(), carrier); !errors.Is(err, ErrMalformedCarrier) {
+        t.Fatalf("err = %v, want ErrMalformedCarrier", err)
+    }
+}
@@ -0,0 +1,180 @@
+// core/rules.go
+
+package core
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+)
+
+// ErrInvalidRules 生成规则不合法
+var ErrInvalidRules = errors.New("invalid generation rules")
+
+// ElementRule 某阶段生成的一个元素，阳性比例为 1 - Yin
+type ElementRule struct {
+    Name   string  `json:"name,omitempty" yaml:"name,omitempty"` // 元素名称，仅用于描述
+    Yin    float64 `json:"yin" yaml:"yin"`                       // 阴性比例 0-1
+    Energy float64 `json:"energy" yaml:"energy"`                 // 初始能量
+}
+
+// WanWuRule 五生万物的参数
+type WanWuRule struct {
+    Transfer  float64 `json:"transfer" yaml:"transfer"`     // 每次生成从母元素转移的能量比例 (0-1]
+    MinEnergy float64 `json:"min_energy" yaml:"min_energy"` // 母元素能量低于此值时不再生成
+    Jitter    float64 `json:"jitter" yaml:"jitter"`         // 新元素阴阳比例的随机扰动幅度
+}
+
+// GenerationRules 无极到万物各阶段的生成规则
+type GenerationRules struct {
+    TaiJi   ElementRule   `json:"taiji" yaml:"taiji"`     // 道生一
+    YinYang []ElementRule `json:"yinyang" yaml:"yinyang"` // 一生二，至少一个元素
+    Triad   []ElementRule `json:"triad" yaml:"triad"`     // 二生三，依次为少阴、少阳、中和
+    WuXing  []ElementRule `json:"wuxing" yaml:"wuxing"`   // 三生五，按相生次序排列，至少一个元素
+    WanWu   WanWuRule     `json:"wanwu" yaml:"wanwu"`     // 五生万物
+}
+
+// DefaultGenerationRules 默认生成规则
+func DefaultGenerationRules() GenerationRules {
+    return GenerationRules{
+        TaiJi: ElementRule{Name: "taiji", Yin: 0.5, Energy: 1.0},
+        YinYang: []ElementRule{
+            {Name: "yin", Yin: 0.8, Energy: 0.5},
+            {Name: "yang", Yin: 0.2, Energy: 0.5},
+        },
+        Triad: []ElementRule{
+            {Name: "minor-yin", Yin: 0.7, Energy: 1.0},
+            {Name: "minor-yang", Yin: 0.3, Energy: 1.0},
+            {Name: "balance", Yin: 0.5, Energy: 1.0},
+        },
+        WuXing: []ElementRule{
+            {Name: "wood", Yin: 0.4, Energy: 1.0},
+            {Name: "fire", Yin: 0.2, Energy: 1.0},
+            {Name: "earth", Yin: 0.5, Energy: 1.0},
+            {Name: "metal", Yin: 0.6, Energy: 1.0},
+            {Name: "water", Yin: 0.8, Energy: 1.0},
+        },
+        WanWu: WanWuRule{
+            Transfer:  0.1,
+            MinEnergy: 0.01,
+            Jitter:    0.05,
+        },
+    }
+}
+
+// RulesDecoder 规则解码函数，签名与 json.Unmarshal 及常见 YAML 库的 Unmarshal 相同
+type RulesDecoder func(data []byte, v interface{}) error
+
+// LoadGenerationRules 读取并校验生成规则，decode 为 nil 时按 JSON 解码
+// 读取 YAML 时传入所用 YAML 库的 Unmarshal
+func LoadGenerationRules(r io.Reader, decode RulesDecoder) (GenerationRules, error) {
+    if decode == nil {
+        decode = json.Unmarshal
+    }
+    data, err := io.ReadAll(r)
+    if err != nil {
+        return GenerationRules{}, err
+    }
+
+    var rules GenerationRules
+    if err := decode(data, &rules); err != nil {
+        return GenerationRules{}, fmt.Errorf("%w: %v", ErrInvalidRules, err)
+    }
+    if err := rules.Validate(); err != nil {
+        return GenerationRules{}, err
+    }
+    return rules, nil
+}
+
+// Validate 校验生成规则
+func (r GenerationRules) Validate() error {
+    check := func(stage string, rules ...ElementRule) error {
+        for i, e := range rules {
+            if e.Yin < 0 || e.Yin > 1 {
+                return fmt.Errorf("%w: %s[%d] yin %v out of [0,1]", ErrInvalidRules, stage, i, e.Yin)
+            }
+            if e.Energy < 0 {
+                return fmt.Errorf("%w: %s[%d] negative energy", ErrInvalidRules, stage, i)
+            }
+        }
+        return nil
+    }
+
+    if err := check("taiji", r.TaiJi); err != nil {
+        return err
+    }
+    if len(r.YinYang) == 0 {
+        return fmt.Errorf("%w: yinyang needs at least one element", ErrInvalidRules)
+    }
+    if err := check("yinyang", r.YinYang...); err != nil {
+        return err
+    }
+    if len(r.Triad) != 3 {
+        return fmt.Errorf("%w: triad needs exactly 3 elements, got %d", ErrInvalidRules, len(r.Triad))
+    }
+    if err := check("triad", r.Triad...); err != nil {
+        return err
+    }
+    if len(r.WuXing) == 0 {
+        return fmt.Errorf("%w: wuxing needs at least one element", ErrInvalidRules)
+    }
+    if err := check("wuxing", r.WuXing...); err != nil {
+        return err
+    }
+
+    if r.WanWu.Transfer <= 0 || r.WanWu.Transfer > 1 {
+        return fmt.Errorf("%w: wanwu transfer %v out of (0,1]", ErrInvalidRules, r.WanWu.Transfer)
+    }
+    if r.WanWu.MinEnergy < 0 || r.WanWu.Jitter < 0 {
+        return fmt.Errorf("%w: wanwu min_energy and jitter must not be negative", ErrInvalidRules)
+    }
+    return nil
+}
+
+// clone 深拷贝规则，避免调用方修改共享的切片
+func (r GenerationRules) clone() GenerationRules {
+    r.YinYang = append([]ElementRule(nil), r.YinYang...)
+    r.Triad = append([]ElementRule(nil), r.Triad...)
+    r.WuXing = append([]ElementRule(nil), r.WuXing...)
+    return r
+}
+
+// element 按规则创建元素
+func (e ElementRule) element(phase Phase) *Element {
+    return &Element{
+        yin:    e.Yin,
+        yang:   1 - e.Yin,
+        energy: e.Energy,
+        phase:  phase,
+    }
+}
+
+// WithGenerationRules 使用指定的生成规则，规则在演化开始时校验
+func WithGenerationRules(rules GenerationRules) UniverseOption {
+    return func(u *Universe) {
+        u.rules = rules.clone()
+    }
+}
+
+// SetGenerationRules 设置生成规则，只能在无极阶段调用
+func (u *Universe) SetGenerationRules(rules GenerationRules) error {
+    if err := rules.Validate(); err != nil {
+        return err
+    }
+
+    u.mu.Lock()
+    defer u.mu.Unlock()
+    if u.phase != UniverseWuJi {
+        return fmt.Errorf("%w: rules can only be set before evolution, current phase %s", ErrInvalidPhase, u.phase)
+    }
+    u.rules = rules.clone()
+    return nil
+}
+
+// GenerationRules 获取生成规则
+func (u *Universe) GenerationRules() GenerationRules {
+    u.mu.RLock()
+    defer u.mu.RUnlock()
+    return u.rules.clone()
+}
@@ -0,0 +1,169 @@
+// core/shutdown.go
+
+package core
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "os"
+    "os/signal"
+    "syscall"
+    "time"
+
+    "github.com/Corphon/daoframe/core/state"
+)
+
+// DefaultShutdownTimeout 未指定截止时间时的关闭时限
+const DefaultShutdownTimeout = time.Second * 30
+
+// ErrShutdownDeadline 关闭超过截止时间
+var ErrShutdownDeadline = errors.New("shutdown deadline exceeded")
+
+// ShutdownPhase 关闭阶段
+type ShutdownPhase uint8
+
+const (
+    ShutdownStopAccepting  ShutdownPhase = iota // 停止接收新的适应周期
+    ShutdownDrain                               // 等待进行中的适应处理器
+    ShutdownFlush                               // 刷新观察者
+    ShutdownStopComponents                      // 逆序停止组件
+    ShutdownTerminate                           // 终止道源
+)
+
+// String 获取阶段名称
+func (p ShutdownPhase) String() string {
+    switch p {
+    case ShutdownStopAccepting:
+        return "stop-accepting"
+    case ShutdownDrain:
+        return "drain"
+    case ShutdownFlush:
+        return "flush"
+    case ShutdownStopComponents:
+        return "stop-components"
+    case ShutdownTerminate:
+        return "terminate"
+    default:
+        return "unknown"
+    }
+}
+
+// Flusher 关闭时需要刷新的观察者
+type Flusher interface {
+    Flush(ctx context.Context) error
+}
+
+// AddFlusher 注册关闭时刷新的观察者
+func (t *TaiJi) AddFlusher(f Flusher) {
+    if f == nil {
+        return
+    }
+    t.origin.mu.Lock()
+    defer t.origin.mu.Unlock()
+    t.origin.flushers = append(t.origin.flushers, f)
+}
+
+// Done 返回关闭完成时关闭的通道
+func (t *TaiJi) Done() <-chan struct{} {
+    return t.origin.done
+}
+
+// Shutdown 按阶段优雅关闭
+// ctx 的截止时间为硬性时限，未设置时使用 DefaultShutdownTimeout；
+// 某阶段超时后剩余阶段仍会执行，以保证组件停止与道源终止
+func (t *TaiJi) Shutdown(ctx context.Context) error {
+    t.shutdownOnce.Do(func() {
+        // 先等待进行中的 Generate 并置为终止，此后的 Generate 返回 ErrInvalidState
+        t.mu.Lock()
+        t.state = state.StateTerminated
+        t.mu.Unlock()
+
+        t.shutdownErr = t.origin.shutdown(ctx)
+    })
+    return t.shutdownErr
+}
+
+// ShutdownOnSignal 阻塞直到收到系统信号或 ctx 结束，然后在 timeout 内完成关闭
+// 关闭期间再次收到信号将立即放弃剩余的等待
+func (t *TaiJi) ShutdownOnSignal(ctx context.Context, timeout time.Duration, signals ...os.Signal) error {
+    if len(signals) == 0 {
+        signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+    }
+    if timeout <= 0 {
+        timeout = DefaultShutdownTimeout
+    }
+
+    sigCh := make(chan os.Signal, 2)
+    signal.Notify(sigCh, signals...)
+    defer signal.Stop(sigCh)
+
+    select {
+    case <-sigCh:
+    case <-ctx.Done():
+    }
+
+    shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+    defer cancel()
+    go func() {
+        select {
+        case <-sigCh:
+            cancel()
+        case <-shutdownCtx.Done():
+        }
+    }()
+
+    return t.Shutdown(shutdownCtx)
+}
+
+// shutdown 依次执行各关闭阶段
+func (o *Origin) shutdown(ctx context.Context) error {
+    if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+        var cancel context.CancelFunc
+        ctx, cancel = context.WithTimeout(ctx, DefaultShutdownTimeout)
+        defer cancel()
+    }
+    defer close(o.done)
+
+    o.mu.RLock()
+    flushers := append([]Flusher(nil), o.flushers...)
+    o.mu.RUnlock()
+
+    phases := []struct {
+        phase ShutdownPhase
+        fn    func(ctx context.Context) error
+    }{
+        {ShutdownStopAccepting, func(context.Context) error {
+            o.energy.Stop()
+            return nil
+        }},
+        {ShutdownDrain, o.energy.Drain},
+        {ShutdownFlush, func(ctx context.Context) error {
+            var errs []error
+            for _, f := range flushers {
+                if err := f.Flush(ctx); err != nil {
+                    errs = append(errs, err)
+                }
+            }
+            return errors.Join(errs...)
+        }},
+        {ShutdownStopComponents, o.components.Stop},
+        {ShutdownTerminate, func(ctx context.Context) error {
+            if o.form.GetState() == state.StateTerminated {
+                return nil
+            }
+            return o.form.Terminate(ctx)
+        }},
+    }
+
+    var errs []error
+    for _, p := range phases {
+        if err := p.fn(ctx); err != nil {
+            if errors.Is(err, context.DeadlineExceeded) {
+                err = fmt.Errorf("%w: %v", ErrShutdownDeadline, err)
+            }
+            errs = append(errs, fmt.Errorf("shutdown %s: %w", p.phase, err))
+        }
+    }
+    return errors.Join(errs...)
+}
@@ -0,0 +1,36 @@
+// core/shutdown_test.go
+
+package core
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "testing"
+
+    "github.com/Corphon/daoframe/core/state"
+)
+
+// TestShutdownRacesGenerate 并发的 Generate 与 Shutdown 之后太极处于终止状态且不能再生成
+func TestShutdownRacesGenerate(t *testing.T) {
+    tj := NewTaiJi()
+
+    var wg sync.WaitGroup
+    wg.Add(2)
+    go func() {
+        defer wg.Done()
+        tj.Generate()
+    }()
+    go func() {
+        defer wg.Done()
+        tj.Shutdown(context.Background())
+    }()
+    wg.Wait()
+
+    if got := tj.State(); got != state.StateTerminated {
+        t.Fatalf("State() = %v, want %v", got, state.StateTerminated)
+    }
+    if _, err := tj.Generate(); !errors.Is(err, ErrInvalidState) {
+        t.Fatalf("Generate after Shutdown = %v, want ErrInvalidState", err)
+    }
+}
@@ -0,0 +1,186 @@
+// core/simulation.go
+
+package core
+
+import (
+    "context"
+    "encoding/csv"
+    "errors"
+    "io"
+    "strconv"
+    "time"
+
+    "github.com/Corphon/daoframe/core/clock"
+)
+
+// ErrStepLimit 达到最大步数仍未满足条件
+var ErrStepLimit = errors.New("simulation step limit reached")
+
+// SimulationConfig 模拟配置
+type SimulationConfig struct {
+    Seed     int64         // 随机种子
+    Start    time.Time     // 虚拟起点，为零值时取 Unix 纪元
+    Interval time.Duration // 每步的虚拟时长，为 0 时取 DefaultTickInterval
+    Rules    *GenerationRules // 生成规则，为 nil 时使用默认规则
+}
+
+// Population 某一步的元素种群统计
+type Population struct {
+    Tick    uint64
+    Time    time.Time
+    Phase   Phase
+    Total   int
+    ByPhase map[Phase]int
+    Energy  float64 // 全部元素的能量总和
+}
+
+// Simulation 步进式的确定性演化
+// 随机源与时钟均由模拟持有，相同配置在任何机器上产生相同的演化
+type Simulation struct {
+    universe    *Universe
+    clock       *clock.Fake
+    interval    time.Duration
+    tick        uint64
+    populations []Population
+}
+
+// NewSimulation 创建模拟并完成无极到五行的演化，记录第 0 步的种群
+func NewSimulation(ctx context.Context, config SimulationConfig) (*Simulation, error) {
+    if ctx == nil {
+        ctx = context.Background()
+    }
+    if config.Start.IsZero() {
+        config.Start = time.Unix(0, 0).UTC()
+    }
+    if config.Interval <= 0 {
+        config.Interval = DefaultTickInterval
+    }
+
+    fake := clock.NewFake(config.Start)
+    opts := []UniverseOption{
+        WithSeed(config.Seed),
+        WithUniverseClock(fake),
+        WithTickInterval(config.Interval),
+    }
+    if config.Rules != nil {
+        opts = append(opts, WithGenerationRules(*config.Rules))
+    }
+    u := NewUniverse(clock.NewContext(ctx, fake), opts...)
+    if err := u.evolvePhases(); err != nil {
+        return nil, err
+    }
+
+    s := &Simulation{
+        universe:    u,
+        clock:       fake,
+        interval:    config.Interval,
+        populations: make([]Population, 0),
+    }
+    s.record()
+    return s, nil
+}
+
+// Universe 获取被模拟的宇宙
+func (s *Simulation) Universe() *Universe {
+    return s.universe
+}
+
+// Clock 获取模拟的虚拟时钟
+func (s *Simulation) Clock() *clock.Fake {
+    return s.clock
+}
+
+// Tick 获取已执行的步数
+func (s *Simulation) Tick() uint64 {
+    return s.tick
+}
+
+// Step 执行 n 步，每步推进一个间隔的虚拟时间并生成一次万物
+func (s *Simulation) Step(n int) {
+    for i := 0; i < n; i++ {
+        s.clock.Advance(s.interval)
+        s.universe.tick()
+        s.tick++
+        s.record()
+    }
+}
+
+// RunUntil 逐步执行直到 cond 对最新种群成立，返回执行的步数
+// 执行 maxSteps 步仍未成立时返回 ErrStepLimit
+func (s *Simulation) RunUntil(cond func(Population) bool, maxSteps int) (int, error) {
+    for i := 0; i < maxSteps; i++ {
+        if cond(s.populations[len(s.populations)-1]) {
+            return i, nil
+        }
+        s.Step(1)
+    }
+    if cond(s.populations[len(s.populations)-1]) {
+        return maxSteps, nil
+    }
+    return maxSteps, ErrStepLimit
+}
+
+// Populations 获取每一步的种群统计
+func (s *Simulation) Populations() []Population {
+    return append([]Population(nil), s.populations...)
+}
+
+// WritePopulationsCSV 以 CSV 导出每一步的种群统计
+func (s *Simulation) WritePopulationsCSV(w io.Writer) error {
+    phases := []Phase{UniverseWuJi, UniverseTaiJi, UniverseYinYang, UniverseTriad, UniverseWuXing, UniverseWanWu}
+
+    cw := csv.NewWriter(w)
+    header := []string{"tick", "time", "phase", "total", "energy"}
+    for _, p := range phases {
+        header = append(header, p.String())
+    }
+    if err := cw.Write(header); err != nil {
+        return err
+    }
+
+    for _, pop := range s.populations {
+        row := []string{
+            strconv.FormatUint(pop.Tick, 10),
+            pop.Time.Format(time.RFC3339Nano),
+            pop.Phase.String(),
+            strconv.Itoa(pop.Total),
+            strconv.FormatFloat(pop.Energy, 'g', -1, 64),
+        }
+        for _, p := range phases {
+            row = append(row, strconv.Itoa(pop.ByPhase[p]))
+        }
+        if err := cw.Write(row); err != nil {
+            return err
+        }
+    }
+    cw.Flush()
+    return cw.Error()
+}
+
+// record 记录当前种群
+func (s *Simulation) record() {
+    pop := s.universe.Population()
+    pop.Tick = s.tick
+    pop.Time = s.clock.Now()
+    s.populations = append(s.populations, pop)
+}
+
+// Population 统计当前元素种群
+func (u *Universe) Population() Population {
+    u.mu.RLock()
+    defer u.mu.RUnlock()
+
+    pop := Population{
+        Time:    u.clock.Now(),
+        Phase:   u.phase,
+        Total:   len(u.elements),
+        ByPhase: make(map[Phase]int),
+    }
+    for _, e := range u.elements {
+        e.mu.RLock()
+        pop.ByPhase[e.phase]++
+        pop.Energy += e.energy
+        e.mu.RUnlock()
+    }
+    return pop
+}
@@ -0,0 +1,50 @@
+// core/simulation_test.go
+
+package core
+
+import (
+    "bytes"
+    "context"
+    "reflect"
+    "testing"
+)
+
+// simulate 以 seed 运行 steps 步，返回种群记录与 CSV 输出
+func simulate(t *testing.T, seed int64, steps int) ([]Population, []byte) {
+    t.Helper()
+    sim, err := NewSimulation(context.Background(), SimulationConfig{Seed: seed})
+    if err != nil {
+        t.Fatal(err)
+    }
+    sim.Step(steps)
+
+    var csv bytes.Buffer
+    if err := sim.WritePopulationsCSV(&csv); err != nil {
+        t.Fatal(err)
+    }
+    return sim.Populations(), csv.Bytes()
+}
+
+func TestSameSeedProducesIdenticalSimulation(t *testing.T) {
+    first, firstCSV := simulate(t, 42, 200)
+    second, secondCSV := simulate(t, 42, 200)
+
+    if !reflect.DeepEqual(first, second) {
+        t.Fatal("populations differ for the same seed")
+    }
+    if !bytes.Equal(firstCSV, secondCSV) {
+        t.Fatal("CSV output differs for the same seed")
+    }
+    if last := first[len(first)-1]; last.Tick != 200 {
+        t.Fatalf("last tick = %d, want 200", last.Tick)
+    }
+}
+
+func TestDifferentSeedsDiverge(t *testing.T) {
+    first, _ := simulate(t, 1, 200)
+    second, _ := simulate(t, 2, 200)
+
+    if reflect.DeepEqual(first, second) {
+        t.Fatal("populations are identical for different seeds")
+    }
+}
@@ -0,0 +1,327 @@
+// core/snapshot.go
+
+package core
+
+import (
+    "bufio"
+    "bytes"
+    "context"
+    "encoding/binary"
+    "encoding/gob"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "time"
+
+    "github.com/Corphon/daoframe/core/clock"
+)
+
+// 快照错误
+var (
+    ErrSnapshotVersion = errors.New("unsupported snapshot version")
+    ErrSnapshotCorrupt = errors.New("corrupt snapshot")
+)
+
+// SnapshotVersion 当前快照格式版本
+const SnapshotVersion = 1
+
+// SnapshotFormat 快照编码格式
+type SnapshotFormat uint8
+
+const (
+    SnapshotJSON   SnapshotFormat = iota // 可读的 JSON
+    SnapshotBinary                       // 魔数 + 版本 + gob
+)
+
+// snapshotMagic 二进制快照的魔数
+var snapshotMagic = []byte("DAOU")
+
+// ElementSnapshot 元素快照
+type ElementSnapshot struct {
+    Yin    float64 `json:"yin"`
+    Yang   float64 `json:"yang"`
+    Energy float64 `json:"energy"`
+    Phase  Phase   `json:"phase"`
+}
+
+// TriadSnapshot 三态快照，取值为元素下标
+type TriadSnapshot struct {
+    MinorYin  int `json:"minor_yin"`
+    MinorYang int `json:"minor_yang"`
+    Balance   int `json:"balance"`
+}
+
+// Snapshot 宇宙快照
+// 三态与五行以元素下标引用元素；随机源以种子与抽取次数恢复，恢复后的演化与原宇宙一致
+type Snapshot struct {
+    Version  int               `json:"version"`
+    Time     time.Time         `json:"time"`
+    Tick     uint64            `json:"tick,omitempty"`
+    Phase    Phase             `json:"phase"`
+    Elements []ElementSnapshot `json:"elements"`
+    Triad    *TriadSnapshot    `json:"triad,omitempty"`
+    WuXing   []int             `json:"wuxing,omitempty"`
+    Seed     int64             `json:"seed"`
+    Draws    uint64            `json:"draws"`
+    Rules    *GenerationRules  `json:"rules,omitempty"`
+}
+
+// Snapshot 生成宇宙快照
+func (u *Universe) Snapshot() (*Snapshot, error) {
+    u.mu.RLock()
+    defer u.mu.RUnlock()
+
+    snap := &Snapshot{
+        Version:  SnapshotVersion,
+        Time:     u.clock.Now(),
+        Phase:    u.phase,
+        Elements: make([]ElementSnapshot, len(u.elements)),
+        Seed:     u.source.seed,
+        Draws:    u.source.draws,
+    }
+    rules := u.rules.clone()
+    snap.Rules = &rules
+
+    index := make(map[*Element]int, len(u.elements))
+    for i, e := range u.elements {
+        index[e] = i
+        e.mu.RLock()
+        snap.Elements[i] = ElementSnapshot{
+            Yin:    e.yin,
+            Yang:   e.yang,
+            Energy: e.energy,
+            Phase:  e.phase,
+        }
+        e.mu.RUnlock()
+    }
+
+    lookup := func(e *Element) (int, error) {
+        i, exists := index[e]
+        if !exists {
+            return 0, fmt.Errorf("%w: element not in universe", ErrSnapshotCorrupt)
+        }
+        return i, nil
+    }
+
+    if u.triad != nil {
+        var err error
+        triad := &TriadSnapshot{}
+        if triad.MinorYin, err = lookup(u.triad.minorYin); err != nil {
+            return nil, err
+        }
+        if triad.MinorYang, err = lookup(u.triad.minorYang); err != nil {
+            return nil, err
+        }
+        if triad.Balance, err = lookup(u.triad.balance); err != nil {
+            return nil, err
+        }
+        snap.Triad = triad
+    }
+
+    if u.wuxing != nil {
+        snap.WuXing = make([]int, 0, len(u.wuxing.elements))
+        for _, e := range u.wuxing.elements {
+            i, err := lookup(e)
+            if err != nil {
+                return nil, err
+            }
+            snap.WuXing = append(snap.WuXing, i)
+        }
+    }
+    return snap, nil
+}
+
+// WriteSnapshot 以指定格式写出宇宙快照
+func (u *Universe) WriteSnapshot(w io.Writer, format SnapshotFormat) error {
+    snap, err := u.Snapshot()
+    if err != nil {
+        return err
+    }
+    return EncodeSnapshot(w, snap, format)
+}
+
+// EncodeSnapshot 编码快照
+func EncodeSnapshot(w io.Writer, snap *Snapshot, format SnapshotFormat) error {
+    switch format {
+    case SnapshotJSON:
+        return json.NewEncoder(w).Encode(snap)
+    case SnapshotBinary:
+        header := make([]byte, len(snapshotMagic)+2)
+        copy(header, snapshotMagic)
+        binary.BigEndian.PutUint16(header[len(snapshotMagic):], uint16(snap.Version))
+        if _, err := w.Write(header); err != nil {
+            return err
+        }
+        return gob.NewEncoder(w).Encode(snap)
+    default:
+        return fmt.Errorf("unknown snapshot format %d", format)
+    }
+}
+
+// DecodeSnapshot 解码快照，自动识别 JSON 与二进制格式
+func DecodeSnapshot(r io.Reader) (*Snapshot, error) {
+    br := bufio.NewReader(r)
+    head, err := br.Peek(len(snapshotMagic))
+    if err != nil && !errors.Is(err, io.EOF) {
+        return nil, err
+    }
+
+    snap := &Snapshot{}
+    if bytes.Equal(head, snapshotMagic) {
+        header := make([]byte, len(snapshotMagic)+2)
+        if _, err := io.ReadFull(br, header); err != nil {
+            return nil, fmt.Errorf("%w: %v", ErrSnapshotCorrupt, err)
+        }
+        version := int(binary.BigEndian.Uint16(header[len(snapshotMagic):]))
+        if version < 1 || version > SnapshotVersion {
+            return nil, fmt.Errorf("%w: %d", ErrSnapshotVersion, version)
+        }
+        if err := gob.NewDecoder(br).Decode(snap); err != nil {
+            return nil, fmt.Errorf("%w: %v", ErrSnapshotCorrupt, err)
+        }
+    } else if err := json.NewDecoder(br).Decode(snap); err != nil {
+        return nil, fmt.Errorf("%w: %v", ErrSnapshotCorrupt, err)
+    }
+
+    if err := snap.validate(); err != nil {
+        return nil, err
+    }
+    return snap, nil
+}
+
+// validate 校验版本、阶段与元素引用
+// 三态自三态阶段起必须存在，五行自五行阶段起必须与生成规则的五行数量一致，更早的阶段不得包含二者
+func (s *Snapshot) validate() error {
+    if s.Version < 1 || s.Version > SnapshotVersion {
+        return fmt.Errorf("%w: %d", ErrSnapshotVersion, s.Version)
+    }
+    if s.Phase > UniverseWanWu {
+        return fmt.Errorf("%w: unknown phase %d", ErrSnapshotCorrupt, s.Phase)
+    }
+    for i, e := range s.Elements {
+        if e.Phase > UniverseWanWu {
+            return fmt.Errorf("%w: element %d has unknown phase %d", ErrSnapshotCorrupt, i, e.Phase)
+        }
+    }
+
+    rules := DefaultGenerationRules()
+    if s.Rules != nil {
+        if err := s.Rules.Validate(); err != nil {
+            return fmt.Errorf("%w: %v", ErrSnapshotCorrupt, err)
+        }
+        rules = *s.Rules
+    }
+
+    inRange := func(i int) bool {
+        return i >= 0 && i < len(s.Elements)
+    }
+    switch {
+    case s.Phase >= UniverseTriad && s.Triad == nil:
+        return fmt.Errorf("%w: phase %v without triad", ErrSnapshotCorrupt, s.Phase)
+    case s.Phase < UniverseTriad && s.Triad != nil:
+        return fmt.Errorf("%w: phase %v with triad", ErrSnapshotCorrupt, s.Phase)
+    case s.Phase >= UniverseWuXing && len(s.WuXing) != len(rules.WuXing):
+        return fmt.Errorf("%w: phase %v needs %d wuxing elements, got %d", ErrSnapshotCorrupt, s.Phase, len(rules.WuXing), len(s.WuXing))
+    case s.Phase < UniverseWuXing && len(s.WuXing) > 0:
+        return fmt.Errorf("%w: phase %v with wuxing", ErrSnapshotCorrupt, s.Phase)
+    }
+    if s.Triad != nil && !(inRange(s.Triad.MinorYin) && inRange(s.Triad.MinorYang) && inRange(s.Triad.Balance)) {
+        return fmt.Errorf("%w: triad element out of range", ErrSnapshotCorrupt)
+    }
+    for _, i := range s.WuXing {
+        if !inRange(i) {
+            return fmt.Errorf("%w: wuxing element %d out of range", ErrSnapshotCorrupt, i)
+        }
+    }
+    return nil
+}
+
+// RestoreUniverse 由快照恢复宇宙，opts 可覆盖随机源、时钟、生成规则等设置以进行分叉实验
+// 恢复的宇宙不会自动开始生成万物，调用 Evolve 自快照阶段继续演化
+func RestoreUniverse(ctx context.Context, snap *Snapshot, opts ...UniverseOption) (*Universe, error) {
+    if err := snap.validate(); err != nil {
+        return nil, err
+    }
+
+    restoreSource := func(u *Universe) {
+        u.source = newCountingSource(snap.Seed)
+        u.source.skip(snap.Draws)
+    }
+    base := []UniverseOption{restoreSource}
+    if snap.Rules != nil {
+        base = append(base, WithGenerationRules(*snap.Rules))
+    }
+    u := NewUniverse(ctx, append(base, opts...)...)
+
+    u.phase = snap.Phase
+    u.elements = make([]*Element, len(snap.Elements))
+    for i, es := range snap.Elements {
+        u.elements[i] = &Element{
+            yin:    es.Yin,
+            yang:   es.Yang,
+            energy: es.Energy,
+            phase:  es.Phase,
+        }
+    }
+
+    if snap.Triad != nil {
+        u.triad = &Triad{
+            minorYin:  u.elements[snap.Triad.MinorYin],
+            minorYang: u.elements[snap.Triad.MinorYang],
+            balance:   u.elements[snap.Triad.Balance],
+        }
+    }
+    if snap.WuXing != nil {
+        elements := make([]*Element, len(snap.WuXing))
+        for i, idx := range snap.WuXing {
+            elements[i] = u.elements[idx]
+        }
+        u.wuxing = newWuXing(elements, u.rules.WanWu)
+    }
+    return u, nil
+}
+
+// Snapshot 生成模拟快照，包含当前步数
+func (s *Simulation) Snapshot() (*Snapshot, error) {
+    snap, err := s.universe.Snapshot()
+    if err != nil {
+        return nil, err
+    }
+    snap.Tick = s.tick
+    return snap, nil
+}
+
+// RestoreSimulation 由快照恢复模拟，虚拟时钟从快照时间继续
+// config.Seed 被忽略，随机序列由快照恢复；config.Rules 不为 nil 时覆盖快照中的生成规则
+func RestoreSimulation(ctx context.Context, snap *Snapshot, config SimulationConfig) (*Simulation, error) {
+    if ctx == nil {
+        ctx = context.Background()
+    }
+    if config.Interval <= 0 {
+        config.Interval = DefaultTickInterval
+    }
+
+    fake := clock.NewFake(snap.Time)
+    opts := []UniverseOption{
+        WithUniverseClock(fake),
+        WithTickInterval(config.Interval),
+    }
+    if config.Rules != nil {
+        opts = append(opts, WithGenerationRules(*config.Rules))
+    }
+    u, err := RestoreUniverse(clock.NewContext(ctx, fake), snap, opts...)
+    if err != nil {
+        return nil, err
+    }
+
+    s := &Simulation{
+        universe:    u,
+        clock:       fake,
+        interval:    config.Interval,
+        tick:        snap.Tick,
+        populations: make([]Population, 0),
+    }
+    s.record()
+    return s, nil
+}
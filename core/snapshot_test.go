@@ -0,0 +1,160 @@
+// core/snapshot_test.go
+
+package core
+
+import (
+    "bytes"
+    "context"
+    "errors"
+    "reflect"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/Corphon/daoframe/core/clock"
+)
+
+func TestDecodeSnapshotRejectsInconsistentPhase(t *testing.T) {
+    cases := map[string]string{
+        "unknown phase":     `{"version":1,"phase":9,"elements":[]}`,
+        "wuxing w/o triad":  `{"version":1,"phase":4,"elements":[]}`,
+        "triad w/o wuxing":  `{"version":1,"phase":4,"elements":[{},{},{}],"triad":{"minor_yin":0,"minor_yang":1,"balance":2}}`,
+        "triad too early":   `{"version":1,"phase":2,"elements":[{}],"triad":{"minor_yin":0,"minor_yang":0,"balance":0}}`,
+        "element out range": `{"version":1,"phase":3,"elements":[{}],"triad":{"minor_yin":0,"minor_yang":1,"balance":0}}`,
+    }
+    for name, data := range cases {
+        if _, err := DecodeSnapshot(strings.NewReader(data)); !errors.Is(err, ErrSnapshotCorrupt) {
+            t.Errorf("%s: err = %v, want ErrSnapshotCorrupt", name, err)
+        }
+    }
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+    sim, err := NewSimulation(context.Background(), SimulationConfig{Seed: 7})
+    if err != nil {
+        t.Fatal(err)
+    }
+    sim.Step(20)
+    snap, err := sim.Snapshot()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    for _, format := range []SnapshotFormat{SnapshotJSON, SnapshotBinary} {
+        var buf bytes.Buffer
+        if err := EncodeSnapshot(&buf, snap, format); err != nil {
+            t.Fatal(err)
+        }
+        decoded, err := DecodeSnapshot(&buf)
+        if err != nil {
+            t.Fatalf("format %d: %v", format, err)
+        }
+        decoded.Time, snap.Time = decoded.Time.UTC(), snap.Time.UTC()
+        if !reflect.DeepEqual(decoded, snap) {
+            t.Fatalf("format %d: decoded snapshot differs", format)
+        }
+    }
+}
+
+func TestRestoredSimulationContinuesIdentically(t *testing.T) {
+    original, err := NewSimulation(context.Background(), SimulationConfig{Seed: 42})
+    if err != nil {
+        t.Fatal(err)
+    }
+    original.Step(30)
+    snap, err := original.Snapshot()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    restored, err := RestoreSimulation(context.Background(), snap, SimulationConfig{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    original.Step(30)
+    restored.Step(30)
+
+    want, got := original.Populations(), restored.Populations()
+    if !reflect.DeepEqual(want[len(want)-1], got[len(got)-1]) {
+        t.Fatalf("restored population %+v, want %+v", got[len(got)-1], want[len(want)-1])
+    }
+}
+
+func TestForkWithDifferentSeedDiverges(t *testing.T) {
+    sim, err := NewSimulation(context.Background(), SimulationConfig{Seed: 1})
+    if err != nil {
+        t.Fatal(err)
+    }
+    sim.Step(10)
+    snap, err := sim.Snapshot()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    fake := clock.NewFake(snap.Time)
+    ctx := clock.NewContext(context.Background(), fake)
+    a, err := RestoreUniverse(ctx, snap)
+    if err != nil {
+        t.Fatal(err)
+    }
+    b, err := RestoreUniverse(ctx, snap, WithSeed(99))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    diverged := false
+    for i := 0; i < 50 && !diverged; i++ {
+        ea, eb := a.tick(), b.tick()
+        diverged = (ea == nil) != (eb == nil) || (ea != nil && ea.yin != eb.yin)
+    }
+    if !diverged {
+        t.Fatal("fork with a different seed did not diverge")
+    }
+}
+
+func TestRestoredUniverseEvolves(t *testing.T) {
+    sim, err := NewSimulation(context.Background(), SimulationConfig{Seed: 3})
+    if err != nil {
+        t.Fatal(err)
+    }
+    snap, err := sim.Snapshot()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    fake := clock.NewFake(snap.Time)
+    u, err := RestoreUniverse(clock.NewContext(context.Background(), fake), snap, WithTickInterval(time.Second))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer u.Stop()
+
+    ticked := make(chan struct{}, 1)
+    u.Subscribe(func(UniverseEvent) {
+        select {
+        case ticked <- struct{}{}:
+        default:
+        }
+    })
+    if err := u.Evolve(); err != nil {
+        t.Fatal(err)
+    }
+    if err := u.Evolve(); !errors.Is(err, ErrUniverseEvolving) {
+        t.Fatalf("second Evolve err = %v, want ErrUniverseEvolving", err)
+    }
+
+    deadline := time.After(5 * time.Second)
+    for {
+        for fake.Waiters() == 0 {
+            time.Sleep(time.Millisecond)
+        }
+        fake.Advance(time.Second)
+        select {
+        case <-ticked:
+            return
+        case <-deadline:
+            t.Fatal("restored universe did not generate elements")
+        case <-time.After(10 * time.Millisecond):
+        }
+    }
+}
@@ -0,0 +1,256 @@
+// core/state/journal.go
+
+package state
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "sync"
+    "time"
+)
+
+// 日志错误
+var (
+    ErrNoJournal       = errors.New("no journal attached")
+    ErrJournalMismatch = errors.New("journal does not match state machine")
+)
+
+// EntryKind 日志条目类型
+type EntryKind string
+
+const (
+    EntryTransition EntryKind = "transition" // 状态转换
+    EntryForce      EntryKind = "force"      // 作用力施加
+)
+
+// Cause 变化的原因与发起者
+type Cause struct {
+    Reason string
+    Actor  string
+}
+
+// JournalEntry 日志条目
+type JournalEntry struct {
+    Sequence  uint64            `json:"seq"`
+    Timestamp time.Time         `json:"ts"`
+    Subject   string            `json:"subject"`
+    Kind      EntryKind         `json:"kind"`
+    Machine   string            `json:"machine,omitempty"`
+    From      State             `json:"from,omitempty"`
+    To        State             `json:"to,omitempty"`
+    Event     Event             `json:"event,omitempty"`
+    Leaves    []State           `json:"leaves,omitempty"`  // 转换后的活动叶子状态
+    History   map[State][]State `json:"history,omitempty"` // 转换后的历史记录
+    Cause     string            `json:"cause,omitempty"`
+    Actor     string            `json:"actor,omitempty"`
+    Detail    map[string]string `json:"detail,omitempty"`
+    Batch     uint64            `json:"batch,omitempty"`     // 所属批次首条目的序号
+    BatchLen  int               `json:"batch_len,omitempty"` // 所属批次的条目数
+}
+
+// JournalBackend 日志持久化后端
+type JournalBackend interface {
+    // Append 追加条目，条目序号已分配
+    Append(ctx context.Context, entry JournalEntry) error
+
+    // Load 按序号升序加载某主体序号大于 after 的条目
+    Load(ctx context.Context, subject string, after uint64) ([]JournalEntry, error)
+
+    // LastSequence 获取某主体最后的序号，无条目时返回 0
+    LastSequence(ctx context.Context, subject string) (uint64, error)
+}
+
+// BatchJournalBackend 支持原子批量追加的日志后端
+type BatchJournalBackend interface {
+    JournalBackend
+
+    // AppendBatch 原子地追加一批条目，要么全部写入，要么全部不写入
+    AppendBatch(ctx context.Context, entries []JournalEntry) error
+}
+
+// Journal 只追加的变化日志，每个主体拥有独立递增的序号
+type Journal struct {
+    mu        sync.Mutex
+    backend   JournalBackend
+    sequences map[string]uint64
+}
+
+// NewJournal 创建日志，backend 为空时使用内存后端
+func NewJournal(backend JournalBackend) *Journal {
+    if backend == nil {
+        backend = NewMemoryJournalBackend()
+    }
+    return &Journal{
+        backend:   backend,
+        sequences: make(map[string]uint64),
+    }
+}
+
+// Append 分配序号与时间戳后追加条目
+func (j *Journal) Append(ctx context.Context, entry JournalEntry) (JournalEntry, error) {
+    j.mu.Lock()
+    defer j.mu.Unlock()
+
+    seq, err := j.lastSequence(ctx, entry.Subject)
+    if err != nil {
+        return entry, err
+    }
+
+    entry.Sequence = seq + 1
+    if entry.Timestamp.IsZero() {
+        entry.Timestamp = time.Now()
+    }
+
+    if err := j.backend.Append(ctx, entry); err != nil {
+        return entry, fmt.Errorf("journal: append %s#%d: %w", entry.Subject, entry.Sequence, err)
+    }
+
+    j.sequences[entry.Subject] = entry.Sequence
+    return entry, nil
+}
+
+// AppendBatch 以一个批次追加同一主体的多个条目
+// 后端实现 BatchJournalBackend 时整批原子写入；否则逐条写入，
+// 各条目记录批次标记，写入中途失败留下的不完整批次在重放时被跳过
+func (j *Journal) AppendBatch(ctx context.Context, entries []JournalEntry) ([]JournalEntry, error) {
+    if len(entries) == 0 {
+        return nil, nil
+    }
+    subject := entries[0].Subject
+    for _, entry := range entries[1:] {
+        if entry.Subject != subject {
+            return nil, fmt.Errorf("journal: batch mixes subjects %s and %s", subject, entry.Subject)
+        }
+    }
+
+    j.mu.Lock()
+    defer j.mu.Unlock()
+
+    seq, err := j.lastSequence(ctx, subject)
+    if err != nil {
+        return nil, err
+    }
+
+    batch := make([]JournalEntry, len(entries))
+    now := time.Now()
+    for i, entry := range entries {
+        entry.Sequence = seq + 1 + uint64(i)
+        entry.Batch = seq + 1
+        entry.BatchLen = len(entries)
+        if entry.Timestamp.IsZero() {
+            entry.Timestamp = now
+        }
+        batch[i] = entry
+    }
+
+    if b, ok := j.backend.(BatchJournalBackend); ok {
+        if err := b.AppendBatch(ctx, batch); err != nil {
+            return nil, fmt.Errorf("journal: append batch %s#%d: %w", subject, batch[0].Sequence, err)
+        }
+    } else {
+        for _, entry := range batch {
+            if err := j.backend.Append(ctx, entry); err != nil {
+                // 部分条目可能已写入，下次追加时从后端重新读取序号
+                delete(j.sequences, subject)
+                return nil, fmt.Errorf("journal: append %s#%d: %w", subject, entry.Sequence, err)
+            }
+        }
+    }
+
+    j.sequences[subject] = batch[len(batch)-1].Sequence
+    return batch, nil
+}
+
+// lastSequence 获取主体最后的序号，首次使用时从后端读取，调用前须持有锁
+func (j *Journal) lastSequence(ctx context.Context, subject string) (uint64, error) {
+    if seq, exists := j.sequences[subject]; exists {
+        return seq, nil
+    }
+    last, err := j.backend.LastSequence(ctx, subject)
+    if err != nil {
+        return 0, fmt.Errorf("journal: load sequence of %s: %w", subject, err)
+    }
+    return last, nil
+}
+
+// complete 过滤掉不完整的批次，保留单独追加的条目与完整批次
+func complete(entries []JournalEntry) []JournalEntry {
+    counts := make(map[uint64]int)
+    for _, entry := range entries {
+        if entry.BatchLen > 0 {
+            counts[entry.Batch]++
+        }
+    }
+
+    kept := make([]JournalEntry, 0, len(entries))
+    for _, entry := range entries {
+        if entry.BatchLen > 0 && counts[entry.Batch] != entry.BatchLen {
+            continue
+        }
+        kept = append(kept, entry)
+    }
+    return kept
+}
+
+// Entries 加载某主体序号大于 after 的条目
+func (j *Journal) Entries(ctx context.Context, subject string, after uint64) ([]JournalEntry, error) {
+    return j.backend.Load(ctx, subject, after)
+}
+
+// MemoryJournalBackend 内存日志后端
+type MemoryJournalBackend struct {
+    mu      sync.RWMutex
+    entries map[string][]JournalEntry
+}
+
+// NewMemoryJournalBackend 创建内存日志后端
+func NewMemoryJournalBackend() *MemoryJournalBackend {
+    return &MemoryJournalBackend{
+        entries: make(map[string][]JournalEntry),
+    }
+}
+
+// Append 追加条目
+func (b *MemoryJournalBackend) Append(ctx context.Context, entry JournalEntry) error {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    b.entries[entry.Subject] = append(b.entries[entry.Subject], entry)
+    return nil
+}
+
+// AppendBatch 原子地追加一批条目
+func (b *MemoryJournalBackend) AppendBatch(ctx context.Context, entries []JournalEntry) error {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    for _, entry := range entries {
+        b.entries[entry.Subject] = append(b.entries[entry.Subject], entry)
+    }
+    return nil
+}
+
+// Load 加载条目
+func (b *MemoryJournalBackend) Load(ctx context.Context, subject string, after uint64) ([]JournalEntry, error) {
+    b.mu.RLock()
+    defer b.mu.RUnlock()
+
+    entries := make([]JournalEntry, 0)
+    for _, entry := range b.entries[subject] {
+        if entry.Sequence > after {
+            entries = append(entries, entry)
+        }
+    }
+    return entries, nil
+}
+
+// LastSequence 获取最后的序号
+func (b *MemoryJournalBackend) LastSequence(ctx context.Context, subject string) (uint64, error) {
+    b.mu.RLock()
+    defer b.mu.RUnlock()
+
+    entries := b.entries[subject]
+    if len(entries) == 0 {
+        return 0, nil
+    }
+    return entries[len(entries)-1].Sequence, nil
+}
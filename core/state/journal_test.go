@@ -0,0 +1,105 @@
+// core/state/journal_test.go
+
+package state
+
+import (
+    "context"
+    "errors"
+    "reflect"
+    "testing"
+)
+
+var errBackendDown = errors.New("backend down")
+
+// flakyBackend 不支持批量追加的后端，第 failAt 次 Append 失败
+type flakyBackend struct {
+    memory  *MemoryJournalBackend
+    appends int
+    failAt  int
+}
+
+func (b *flakyBackend) Append(ctx context.Context, entry JournalEntry) error {
+    b.appends++
+    if b.appends == b.failAt {
+        return errBackendDown
+    }
+    return b.memory.Append(ctx, entry)
+}
+
+func (b *flakyBackend) Load(ctx context.Context, subject string, after uint64) ([]JournalEntry, error) {
+    return b.memory.Load(ctx, subject, after)
+}
+
+func (b *flakyBackend) LastSequence(ctx context.Context, subject string) (uint64, error) {
+    return b.memory.LastSequence(ctx, subject)
+}
+
+func TestPartialBatchIsSkippedOnReplay(t *testing.T) {
+    def := parallelDefinition()
+    def.Transitions = append(def.Transitions, Transition{From: "B1", To: "B2", Event: "next"})
+    m, err := NewMachine(def)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // 一次 next 同时转换两个区域，第二条日志写入失败
+    backend := &flakyBackend{memory: NewMemoryJournalBackend(), failAt: 2}
+    journal := NewJournal(backend)
+    sm := NewStateManager(m, WithJournal(journal, "p"))
+    if err := sm.Fire("next", nil); !errors.Is(err, errBackendDown) {
+        t.Fatalf("Fire = %v, want errBackendDown", err)
+    }
+
+    initial := []string{"P", "P.A", "P.A.A1", "P.B", "P.B.B1"}
+    if got := sm.Snapshot().Active; !reflect.DeepEqual(got, initial) {
+        t.Fatalf("active after failed commit = %v, want %v", got, initial)
+    }
+    restored := NewStateManager(m, WithJournal(journal, "p"))
+    if err := restored.Replay(context.Background()); err != nil {
+        t.Fatal(err)
+    }
+    if got := restored.Snapshot().Active; !reflect.DeepEqual(got, initial) {
+        t.Fatalf("active after replaying partial batch = %v, want %v", got, initial)
+    }
+
+    // 再次提交时序号接续已写入的条目
+    if err := sm.Fire("next", nil); err != nil {
+        t.Fatal(err)
+    }
+    entries, err := journal.Entries(context.Background(), "p", 0)
+    if err != nil {
+        t.Fatal(err)
+    }
+    for i, entry := range entries {
+        if entry.Sequence != uint64(i+1) {
+            t.Fatalf("entry %d has sequence %d", i, entry.Sequence)
+        }
+    }
+    want := []string{"P", "P.A", "P.A.A2", "P.B", "P.B.B2"}
+    if err := restored.Replay(context.Background()); err != nil {
+        t.Fatal(err)
+    }
+    if got := restored.Snapshot().Active; !reflect.DeepEqual(got, want) {
+        t.Fatalf("active after replay = %v, want %v", got, want)
+    }
+}
+
+func TestMemoryBackendAppendsBatchAtomically(t *testing.T) {
+    journal := NewJournal(nil)
+    batch, err := journal.AppendBatch(context.Background(), []JournalEntry{
+        {Subject: "s", Kind: EntryTransition},
+        {Subject: "s", Kind: EntryTransition},
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    for i, entry := range batch {
+        if entry.Sequence != uint64(i+1) || entry.Batch != 1 || entry.BatchLen != 2 {
+            t.Fatalf("entry %d = seq %d batch %d/%d", i, entry.Sequence, entry.Batch, entry.BatchLen)
+        }
+    }
+
+    if _, err := journal.AppendBatch(context.Background(), []JournalEntry{{Subject: "s"}, {Subject: "t"}}); err == nil {
+        t.Fatal("AppendBatch accepted entries of different subjects")
+    }
+}
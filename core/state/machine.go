@@ -0,0 +1,431 @@
+// core/state/machine.go
+
+package state
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+)
+
+// 状态机错误
+var (
+    ErrInvalidDefinition = errors.New("invalid state machine definition")
+    ErrUnknownState      = errors.New("unknown state")
+    ErrInvalidTransition = errors.New("invalid state transition")
+    ErrGuardRejected     = errors.New("transition rejected by guard")
+    ErrNoTransition      = errors.New("no transition for event")
+)
+
+// PathSeparator 状态路径分隔符，如 "Active.Growth"
+const PathSeparator = "."
+
+// Event 触发状态转换的事件
+type Event string
+
+// Guard 转换守卫，返回 false 时拒绝转换
+// 守卫执行时不持有管理器的锁，可调用管理器的读取方法，读到的是转换前的配置；
+// 后续转换须经 tc.Fire 或 tc.TransitTo 发起，直接调用管理器的 Fire 或 TransitTo 会死锁
+type Guard func(tc *TransitionContext) bool
+
+// Action 入口、出口及转换动作，返回错误时整个转换不生效
+// 动作执行时不持有管理器的锁，读取方法返回转换前的配置；
+// 后续转换须经 tc.Fire 或 tc.TransitTo 排队，待当前转换提交后执行，
+// 直接调用管理器的 Fire 或 TransitTo 会等待当前转换结束而死锁
+type Action func(tc *TransitionContext) error
+
+// HistoryType 复合状态的历史类型
+type HistoryType uint8
+
+const (
+    HistoryNone    HistoryType = iota // 不记录，总是进入默认子状态
+    HistoryShallow                    // 记录直接子状态
+    HistoryDeep                       // 记录全部后代状态
+)
+
+// TransitionContext 转换上下文，传递给守卫与动作
+type TransitionContext struct {
+    Machine string
+    From    State
+    To      State
+    Event   Event
+    Cause   Cause
+    Payload interface{}
+
+    manager *StateManager
+}
+
+// Fire 排队触发事件，在当前转换提交后执行，其错误由发起当前转换的调用返回
+// 只应在守卫与动作执行期间调用
+func (tc *TransitionContext) Fire(event Event, payload interface{}) error {
+    sm := tc.manager
+    sm.enqueue(func() error {
+        return sm.fire(event, tc.Cause, payload)
+    })
+    return nil
+}
+
+// TransitTo 排队转向目标状态，在当前转换提交后执行，其错误由发起当前转换的调用返回
+// 只应在守卫与动作执行期间调用
+func (tc *TransitionContext) TransitTo(to State, payload interface{}) error {
+    sm := tc.manager
+    if !sm.machine.HasState(to) {
+        return fmt.Errorf("%w: %v", ErrUnknownState, to)
+    }
+    sm.enqueue(func() error {
+        return sm.transit(to, tc.Cause, payload)
+    })
+    return nil
+}
+
+// StateSpec 状态声明
+type StateSpec struct {
+    Name     State
+    Parent   State       // 父状态，为空表示顶层状态
+    Initial  State       // 复合状态的默认子状态，为空时取首个子状态
+    Parallel bool        // 子状态作为并行区域同时激活
+    History  HistoryType // 离开该状态时记录的历史
+    OnEntry  Action      // 进入状态时执行
+    OnExit   Action      // 离开状态时执行
+}
+
+// Transition 转换声明
+// From 可以是复合状态，此时其任一后代处于活动状态时均可触发
+type Transition struct {
+    From   State
+    To     State
+    Event  Event  // 触发事件，可为空
+    Guard  Guard  // 守卫，可为空
+    Action Action // 转换动作，在出口动作之后、入口动作之前执行
+}
+
+// Definition 状态机声明
+type Definition struct {
+    Name        string
+    Initial     State
+    States      []StateSpec
+    Transitions []Transition
+}
+
+// node 编译后的状态节点
+type node struct {
+    spec     *StateSpec
+    children []State
+    initial  State
+    index    int    // 先序遍历序号
+    path     string // 完整路径
+}
+
+// Machine 编译后的状态机
+// Machine 不可变，可被任意多个 StateManager 共享
+type Machine struct {
+    name     string
+    initial  State
+    order    []State // 先序遍历顺序
+    nodes    map[State]*node
+    outgoing map[State][]*Transition
+}
+
+// NewMachine 校验声明并编译状态机
+func NewMachine(def Definition) (*Machine, error) {
+    if def.Name == "" {
+        return nil, fmt.Errorf("%w: name is required", ErrInvalidDefinition)
+    }
+
+    m := &Machine{
+        name:     def.Name,
+        initial:  def.Initial,
+        order:    make([]State, 0, len(def.States)),
+        nodes:    make(map[State]*node, len(def.States)),
+        outgoing: make(map[State][]*Transition),
+    }
+
+    declared := make([]State, 0, len(def.States))
+    for i := range def.States {
+        spec := def.States[i]
+        if spec.Name == "" {
+            return nil, fmt.Errorf("%w: %s: state name is required", ErrInvalidDefinition, def.Name)
+        }
+        if strings.Contains(string(spec.Name), PathSeparator) {
+            return nil, fmt.Errorf("%w: %s: state name %q contains %q", ErrInvalidDefinition, def.Name, spec.Name, PathSeparator)
+        }
+        if _, exists := m.nodes[spec.Name]; exists {
+            return nil, fmt.Errorf("%w: %s: duplicate state %v", ErrInvalidDefinition, def.Name, spec.Name)
+        }
+        m.nodes[spec.Name] = &node{spec: &spec}
+        declared = append(declared, spec.Name)
+    }
+
+    if err := m.buildTree(declared); err != nil {
+        return nil, err
+    }
+
+    if _, exists := m.nodes[def.Initial]; !exists {
+        return nil, fmt.Errorf("%w: %s: initial state %v not declared", ErrInvalidDefinition, def.Name, def.Initial)
+    }
+
+    for i := range def.Transitions {
+        tr := def.Transitions[i]
+        if _, exists := m.nodes[tr.From]; !exists {
+            return nil, fmt.Errorf("%w: %s: transition from undeclared state %v", ErrInvalidDefinition, def.Name, tr.From)
+        }
+        if _, exists := m.nodes[tr.To]; !exists {
+            return nil, fmt.Errorf("%w: %s: transition to undeclared state %v", ErrInvalidDefinition, def.Name, tr.To)
+        }
+        m.outgoing[tr.From] = append(m.outgoing[tr.From], &tr)
+    }
+
+    return m, nil
+}
+
+// buildTree 建立状态层次并计算先序顺序与路径
+func (m *Machine) buildTree(declared []State) error {
+    roots := make([]State, 0)
+    for _, name := range declared {
+        n := m.nodes[name]
+        parent := n.spec.Parent
+        if parent == "" {
+            roots = append(roots, name)
+            continue
+        }
+        p, exists := m.nodes[parent]
+        if !exists {
+            return fmt.Errorf("%w: %s: parent %v of %v not declared", ErrInvalidDefinition, m.name, parent, name)
+        }
+        p.children = append(p.children, name)
+    }
+
+    var visit func(s State, prefix string) error
+    visit = func(s State, prefix string) error {
+        n := m.nodes[s]
+        if n.path != "" {
+            return fmt.Errorf("%w: %s: cyclic hierarchy at %v", ErrInvalidDefinition, m.name, s)
+        }
+        n.path = prefix + string(s)
+        n.index = len(m.order)
+        m.order = append(m.order, s)
+
+        if len(n.children) > 0 {
+            n.initial = n.spec.Initial
+            if n.initial == "" {
+                n.initial = n.children[0]
+            } else if m.parentOf(n.initial) != s {
+                return fmt.Errorf("%w: %s: initial %v is not a child of %v", ErrInvalidDefinition, m.name, n.initial, s)
+            }
+        }
+
+        for _, child := range n.children {
+            if err := visit(child, n.path+PathSeparator); err != nil {
+                return err
+            }
+        }
+        return nil
+    }
+
+    for _, root := range roots {
+        if err := visit(root, ""); err != nil {
+            return err
+        }
+    }
+
+    if len(m.order) != len(declared) {
+        return fmt.Errorf("%w: %s: cyclic hierarchy", ErrInvalidDefinition, m.name)
+    }
+    return nil
+}
+
+// MustNewMachine 编译状态机，声明无效时 panic
+func MustNewMachine(def Definition) *Machine {
+    m, err := NewMachine(def)
+    if err != nil {
+        panic(err)
+    }
+    return m
+}
+
+// Name 获取状态机名称
+func (m *Machine) Name() string {
+    return m.name
+}
+
+// Initial 获取初始状态
+func (m *Machine) Initial() State {
+    return m.initial
+}
+
+// HasState 检查状态是否已声明
+func (m *Machine) HasState(s State) bool {
+    _, exists := m.nodes[s]
+    return exists
+}
+
+// States 按层次先序返回所有状态
+func (m *Machine) States() []State {
+    return append([]State(nil), m.order...)
+}
+
+// Parent 获取父状态，顶层状态返回空
+func (m *Machine) Parent(s State) State {
+    return m.parentOf(s)
+}
+
+// Children 获取子状态
+func (m *Machine) Children(s State) []State {
+    if n, exists := m.nodes[s]; exists {
+        return append([]State(nil), n.children...)
+    }
+    return nil
+}
+
+// Path 获取状态的完整路径
+func (m *Machine) Path(s State) string {
+    if n, exists := m.nodes[s]; exists {
+        return n.path
+    }
+    return ""
+}
+
+// Resolve 由完整路径解析状态
+func (m *Machine) Resolve(path string) (State, bool) {
+    segments := strings.Split(path, PathSeparator)
+    s := State(segments[len(segments)-1])
+    if n, exists := m.nodes[s]; exists && n.path == path {
+        return s, true
+    }
+    return "", false
+}
+
+// Targets 获取某状态可直接转向的状态（含祖先声明的转换，不计守卫）
+func (m *Machine) Targets(from State) []State {
+    seen := make(map[State]bool)
+    targets := make([]State, 0)
+    for _, s := range m.ancestors(from) {
+        for _, tr := range m.outgoing[s] {
+            if !seen[tr.To] {
+                seen[tr.To] = true
+                targets = append(targets, tr.To)
+            }
+        }
+    }
+    return targets
+}
+
+// CanTransit 检查转换是否在图中声明（含祖先声明的转换，不计守卫）
+func (m *Machine) CanTransit(from, to State) bool {
+    for _, s := range m.ancestors(from) {
+        for _, tr := range m.outgoing[s] {
+            if tr.To == to {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+// parentOf 获取父状态
+func (m *Machine) parentOf(s State) State {
+    if n, exists := m.nodes[s]; exists {
+        return n.spec.Parent
+    }
+    return ""
+}
+
+// ancestors 自下而上返回状态及其全部祖先
+func (m *Machine) ancestors(s State) []State {
+    chain := make([]State, 0, 4)
+    for ; s != ""; s = m.parentOf(s) {
+        chain = append(chain, s)
+    }
+    return chain
+}
+
+// isDescendant 检查 s 是否为 anc 的真后代，anc 为空表示根
+func (m *Machine) isDescendant(s, anc State) bool {
+    if anc == "" {
+        return s != ""
+    }
+    for p := m.parentOf(s); p != ""; p = m.parentOf(p) {
+        if p == anc {
+            return true
+        }
+    }
+    return false
+}
+
+// domain 获取转换作用域：源与目标最近的、非并行的共同真祖先，为空表示根
+// 跨越并行区域的转换以并行状态的父状态为作用域，整个并行状态退出后再进入，未涉及的区域按默认方式进入
+func (m *Machine) domain(from, to State) State {
+    for p := m.parentOf(from); p != ""; p = m.parentOf(p) {
+        if m.isDescendant(to, p) && !m.nodes[p].spec.Parallel {
+            return p
+        }
+    }
+    return ""
+}
+
+// entryOrder 计算从作用域进入目标状态时需激活的状态，自上而下
+func (m *Machine) entryOrder(target, domain State, history map[State][]State) []State {
+    path := make([]State, 0, 4)
+    for s := target; s != domain && s != ""; s = m.parentOf(s) {
+        path = append([]State{s}, path...)
+    }
+
+    onPath := make(map[State]bool, len(path))
+    for _, s := range path {
+        onPath[s] = true
+    }
+
+    entered := make([]State, 0, len(path))
+    for _, s := range path {
+        entered = append(entered, s)
+        if m.nodes[s].spec.Parallel {
+            for _, child := range m.nodes[s].children {
+                if !onPath[child] {
+                    entered = m.appendDefault(entered, child, history)
+                }
+            }
+        }
+    }
+
+    return m.appendCompletion(entered, target, history)
+}
+
+// appendDefault 以默认方式进入状态
+func (m *Machine) appendDefault(entered []State, s State, history map[State][]State) []State {
+    return m.appendCompletion(append(entered, s), s, history)
+}
+
+// appendCompletion 补全复合或并行状态的后代
+func (m *Machine) appendCompletion(entered []State, s State, history map[State][]State) []State {
+    n := m.nodes[s]
+    if len(n.children) == 0 {
+        return entered
+    }
+
+    if n.spec.Parallel {
+        for _, child := range n.children {
+            entered = m.appendDefault(entered, child, history)
+        }
+        return entered
+    }
+
+    if recorded, exists := history[s]; exists && n.spec.History != HistoryNone {
+        if n.spec.History == HistoryDeep {
+            return append(entered, recorded...)
+        }
+        for _, child := range recorded {
+            entered = m.appendDefault(entered, child, history)
+        }
+        return entered
+    }
+
+    return m.appendDefault(entered, n.initial, history)
+}
+
+// spec 获取状态声明
+func (m *Machine) spec(s State) *StateSpec {
+    if n, exists := m.nodes[s]; exists {
+        return n.spec
+    }
+    return nil
+}
@@ -0,0 +1,82 @@
+// core/state/machine_test.go
+
+package state
+
+import (
+    "reflect"
+    "testing"
+)
+
+// parallelDefinition 并行状态 P 含区域 A{A1,A2} 与 B{B1,B2}
+func parallelDefinition() Definition {
+    return Definition{
+        Name:    "parallel",
+        Initial: "P",
+        States: []StateSpec{
+            {Name: "P", Parallel: true},
+            {Name: "A", Parent: "P"},
+            {Name: "A1", Parent: "A"},
+            {Name: "A2", Parent: "A"},
+            {Name: "B", Parent: "P"},
+            {Name: "B1", Parent: "B"},
+            {Name: "B2", Parent: "B"},
+        },
+        Transitions: []Transition{
+            {From: "A1", To: "A2", Event: "next"},
+            {From: "A1", To: "B2", Event: "cross"},
+        },
+    }
+}
+
+func TestCrossRegionTransitionReentersParallel(t *testing.T) {
+    var exited, entered []State
+    def := parallelDefinition()
+    for i := range def.States {
+        name := def.States[i].Name
+        def.States[i].OnExit = func(*TransitionContext) error {
+            exited = append(exited, name)
+            return nil
+        }
+        def.States[i].OnEntry = func(*TransitionContext) error {
+            entered = append(entered, name)
+            return nil
+        }
+    }
+    m, err := NewMachine(def)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    sm := NewStateManager(m)
+    if err := sm.Fire("cross", nil); err != nil {
+        t.Fatal(err)
+    }
+
+    want := []string{"P", "P.A", "P.A.A1", "P.B", "P.B.B2"}
+    if got := sm.Snapshot().Active; !reflect.DeepEqual(got, want) {
+        t.Fatalf("active = %v, want %v", got, want)
+    }
+    if want := []State{"B1", "B", "A1", "A", "P"}; !reflect.DeepEqual(exited, want) {
+        t.Fatalf("exited = %v, want %v", exited, want)
+    }
+    if want := []State{"P", "A", "A1", "B", "B2"}; !reflect.DeepEqual(entered, want) {
+        t.Fatalf("entered = %v, want %v", entered, want)
+    }
+}
+
+func TestTransitionWithinRegionKeepsSibling(t *testing.T) {
+    m, err := NewMachine(parallelDefinition())
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    sm := NewStateManager(m)
+    if err := sm.Fire("next", nil); err != nil {
+        t.Fatal(err)
+    }
+
+    want := []string{"P", "P.A", "P.A.A2", "P.B", "P.B.B1"}
+    if got := sm.Snapshot().Active; !reflect.DeepEqual(got, want) {
+        t.Fatalf("active = %v, want %v", got, want)
+    }
+}
@@ -0,0 +1,629 @@
+// core/state/manager.go
+
+package state
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+)
+
+// TransitionEvent 转换完成后发出的事件
+type TransitionEvent struct {
+    Machine   string
+    From      State
+    To        State
+    Event     Event
+    Cause     Cause
+    Payload   interface{}
+    Timestamp time.Time
+}
+
+// Listener 转换监听器，在转换提交后调用
+type Listener func(evt TransitionEvent)
+
+// Configuration 活动状态配置快照
+type Configuration struct {
+    Machine string
+    Active  []string          // 全部活动状态路径，按层次先序排列
+    Leaves  []State           // 活动叶子状态
+    History map[State][]State // 复合状态的历史记录
+}
+
+// ManagerOption 状态管理器选项
+type ManagerOption func(*StateManager)
+
+// WithInitialState 指定初始状态，未在状态机中声明时忽略
+func WithInitialState(s State) ManagerOption {
+    return func(sm *StateManager) {
+        if sm.machine.HasState(s) {
+            sm.initial = s
+            sm.active = sm.configFor(s)
+        }
+    }
+}
+
+// WithListener 注册转换监听器
+func WithListener(l Listener) ManagerOption {
+    return func(sm *StateManager) {
+        if l != nil {
+            sm.listeners = append(sm.listeners, l)
+        }
+    }
+}
+
+// WithJournal 将转换写入日志，subject 为该管理器在日志中的主体标识
+func WithJournal(j *Journal, subject string) ManagerOption {
+    return func(sm *StateManager) {
+        sm.journal = j
+        sm.subject = subject
+    }
+}
+
+// StateManager 状态管理器，按状态机声明原子地执行转换
+// 支持嵌套状态、历史状态与并行区域，活动配置为一组同时激活的状态
+//
+// 转换依次执行：守卫与动作在工作副本上运行，不持有管理器的锁，
+// 其中调用 Current、Is、Snapshot 等读取的是转换前已提交的配置。
+// 转换进行中其他调用者发起的 Fire、TransitTo 与 Replay 等待当前转换结束后执行并返回各自的错误；
+// 守卫与动作经 TransitionContext 发起的后续转换排队，在当前转换提交后执行
+type StateManager struct {
+    mu        sync.RWMutex
+    machine   *Machine
+    initial   State // 初始状态，重放自此开始
+    active    map[State]bool
+    history   map[State][]State
+    listeners []Listener
+    journal   *Journal
+    subject   string
+    idle      *sync.Cond     // 转换结束时通知等待者，基于 mu
+    busy      bool           // 正在执行转换
+    pending   []func() error // 守卫与动作排队的后续转换
+}
+
+// NewStateManager 创建状态管理器
+func NewStateManager(m *Machine, opts ...ManagerOption) *StateManager {
+    sm := &StateManager{
+        machine:   m,
+        initial:   m.Initial(),
+        history:   make(map[State][]State),
+        listeners: make([]Listener, 0),
+    }
+    sm.idle = sync.NewCond(&sm.mu)
+    sm.active = sm.configFor(m.Initial())
+
+    for _, opt := range opts {
+        opt(sm)
+    }
+
+    return sm
+}
+
+// configFor 计算进入某状态后的活动配置（不执行动作）
+func (sm *StateManager) configFor(s State) map[State]bool {
+    active := make(map[State]bool)
+    for _, entered := range sm.machine.entryOrder(s, "", sm.history) {
+        active[entered] = true
+    }
+    return active
+}
+
+// Machine 获取所使用的状态机
+func (sm *StateManager) Machine() *Machine {
+    return sm.machine
+}
+
+// Current 获取当前叶子状态，存在并行区域时返回首个区域的叶子状态
+func (sm *StateManager) Current() State {
+    sm.mu.RLock()
+    defer sm.mu.RUnlock()
+
+    leaves := sm.leaves(sm.active)
+    if len(leaves) == 0 {
+        return ""
+    }
+    return leaves[0]
+}
+
+// Is 检查状态是否处于活动配置中，复合状态在任一后代活动时亦为真
+func (sm *StateManager) Is(s State) bool {
+    sm.mu.RLock()
+    defer sm.mu.RUnlock()
+    return sm.active[s]
+}
+
+// IsIn 按路径模式检查活动配置，"*" 匹配任意单段
+// 如 "Active" 、"Active.Growth" 、"Active.*"
+func (sm *StateManager) IsIn(pattern string) bool {
+    sm.mu.RLock()
+    defer sm.mu.RUnlock()
+
+    want := strings.Split(pattern, PathSeparator)
+    for s := range sm.active {
+        if matchPath(want, strings.Split(sm.machine.Path(s), PathSeparator)) {
+            return true
+        }
+    }
+    return false
+}
+
+// matchPath 逐段匹配路径
+func matchPath(pattern, path []string) bool {
+    if len(pattern) != len(path) {
+        return false
+    }
+    for i := range pattern {
+        if pattern[i] != "*" && pattern[i] != path[i] {
+            return false
+        }
+    }
+    return true
+}
+
+// Can 检查能否从当前配置转向目标状态（不计守卫）
+func (sm *StateManager) Can(to State) bool {
+    sm.mu.RLock()
+    defer sm.mu.RUnlock()
+
+    for _, leaf := range sm.leaves(sm.active) {
+        if sm.machine.CanTransit(leaf, to) {
+            return true
+        }
+    }
+    return false
+}
+
+// Snapshot 获取完整活动配置的快照
+func (sm *StateManager) Snapshot() Configuration {
+    sm.mu.RLock()
+    defer sm.mu.RUnlock()
+
+    cfg := Configuration{
+        Machine: sm.machine.Name(),
+        Active:  make([]string, 0, len(sm.active)),
+        Leaves:  sm.leaves(sm.active),
+        History: make(map[State][]State, len(sm.history)),
+    }
+    for _, s := range sm.ordered(sm.active) {
+        cfg.Active = append(cfg.Active, sm.machine.Path(s))
+    }
+    for s, recorded := range sm.history {
+        cfg.History[s] = append([]State(nil), recorded...)
+    }
+    return cfg
+}
+
+// Subscribe 注册转换监听器
+func (sm *StateManager) Subscribe(l Listener) {
+    if l == nil {
+        return
+    }
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+    sm.listeners = append(sm.listeners, l)
+}
+
+// AttachJournal 挂接日志，之后的转换均写入日志
+func (sm *StateManager) AttachJournal(j *Journal, subject string) {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+    sm.journal = j
+    sm.subject = subject
+}
+
+// TransitTo 转向目标状态
+func (sm *StateManager) TransitTo(to State) error {
+    return sm.TransitBy(to, Cause{}, nil)
+}
+
+// TransitToWith 携带负载转向目标状态，负载传递给守卫与动作
+func (sm *StateManager) TransitToWith(to State, payload interface{}) error {
+    return sm.TransitBy(to, Cause{}, payload)
+}
+
+// TransitBy 以指定原因与发起者转向目标状态
+func (sm *StateManager) TransitBy(to State, cause Cause, payload interface{}) error {
+    if !sm.machine.HasState(to) {
+        return fmt.Errorf("%w: %v", ErrUnknownState, to)
+    }
+    return sm.process(func() error {
+        return sm.transit(to, cause, payload)
+    })
+}
+
+// transit 在工作副本上查找并执行转向目标状态的转换
+func (sm *StateManager) transit(to State, cause Cause, payload interface{}) error {
+    active, history := sm.working()
+
+    declared := false
+    visited := make(map[State]bool)
+    for _, leaf := range sm.leaves(active) {
+        for _, s := range sm.machine.ancestors(leaf) {
+            if visited[s] {
+                continue
+            }
+            visited[s] = true
+            for _, tr := range sm.machine.outgoing[s] {
+                if tr.To != to {
+                    continue
+                }
+                declared = true
+                if sm.allowed(tr, cause, payload) {
+                    return sm.commit(active, history, []*Transition{tr}, cause, payload)
+                }
+            }
+        }
+    }
+
+    if declared {
+        return fmt.Errorf("%w: %v", ErrGuardRejected, to)
+    }
+    current := State("")
+    if leaves := sm.leaves(active); len(leaves) > 0 {
+        current = leaves[0]
+    }
+    return fmt.Errorf("%w: %s: %v -> %v", ErrInvalidTransition, sm.machine.Name(), current, to)
+}
+
+// Fire 触发事件
+// 每个活动叶子自内向外选取首个满足守卫的转换，互不冲突的转换在同一步中执行
+func (sm *StateManager) Fire(event Event, payload interface{}) error {
+    return sm.FireBy(event, Cause{}, payload)
+}
+
+// FireBy 以指定原因与发起者触发事件
+func (sm *StateManager) FireBy(event Event, cause Cause, payload interface{}) error {
+    return sm.process(func() error {
+        return sm.fire(event, cause, payload)
+    })
+}
+
+// fire 在工作副本上选取并执行由事件触发的转换
+func (sm *StateManager) fire(event Event, cause Cause, payload interface{}) error {
+    active, history := sm.working()
+
+    selected := make([]*Transition, 0, 1)
+    exiting := make(map[State]bool)
+    for _, leaf := range sm.leaves(active) {
+        tr := sm.selectFor(leaf, event, cause, payload)
+        if tr == nil {
+            continue
+        }
+        exits := sm.exitSet(active, tr)
+        conflict := false
+        for _, s := range exits {
+            if exiting[s] {
+                conflict = true
+                break
+            }
+        }
+        if conflict {
+            continue
+        }
+        for _, s := range exits {
+            exiting[s] = true
+        }
+        selected = append(selected, tr)
+    }
+
+    if len(selected) == 0 {
+        return fmt.Errorf("%w: %s: %q", ErrNoTransition, sm.machine.Name(), event)
+    }
+    return sm.commit(active, history, selected, cause, payload)
+}
+
+// process 依次执行请求：转换进行中时等待其结束，
+// 随后执行请求及其排队的后续转换，返回全部错误
+func (sm *StateManager) process(req func() error) error {
+    sm.mu.Lock()
+    for sm.busy {
+        sm.idle.Wait()
+    }
+    sm.busy = true
+    sm.mu.Unlock()
+
+    err := req()
+    for {
+        sm.mu.Lock()
+        if len(sm.pending) == 0 {
+            sm.busy = false
+            sm.idle.Signal()
+            sm.mu.Unlock()
+            return err
+        }
+        next := sm.pending[0]
+        sm.pending = sm.pending[1:]
+        sm.mu.Unlock()
+
+        if nextErr := next(); nextErr != nil {
+            err = errors.Join(err, nextErr)
+        }
+    }
+}
+
+// enqueue 排队后续转换，由正在执行的 process 在返回前执行
+func (sm *StateManager) enqueue(req func() error) {
+    sm.mu.Lock()
+    defer sm.mu.Unlock()
+    sm.pending = append(sm.pending, req)
+}
+
+// working 复制已提交的活动配置与历史作为工作副本
+func (sm *StateManager) working() (map[State]bool, map[State][]State) {
+    sm.mu.RLock()
+    defer sm.mu.RUnlock()
+
+    active := make(map[State]bool, len(sm.active))
+    for s := range sm.active {
+        active[s] = true
+    }
+    history := make(map[State][]State, len(sm.history))
+    for s, recorded := range sm.history {
+        history[s] = recorded
+    }
+    return active, history
+}
+
+// selectFor 自叶子向外查找由事件触发且满足守卫的转换
+func (sm *StateManager) selectFor(leaf State, event Event, cause Cause, payload interface{}) *Transition {
+    for _, s := range sm.machine.ancestors(leaf) {
+        for _, tr := range sm.machine.outgoing[s] {
+            if tr.Event == event && sm.allowed(tr, cause, payload) {
+                return tr
+            }
+        }
+    }
+    return nil
+}
+
+// allowed 检查守卫
+func (sm *StateManager) allowed(tr *Transition, cause Cause, payload interface{}) bool {
+    if tr.Guard == nil {
+        return true
+    }
+    return tr.Guard(sm.contextFor(tr, cause, payload))
+}
+
+// contextFor 构造转换上下文
+func (sm *StateManager) contextFor(tr *Transition, cause Cause, payload interface{}) *TransitionContext {
+    return &TransitionContext{
+        Machine: sm.machine.Name(),
+        From:    tr.From,
+        To:      tr.To,
+        Event:   tr.Event,
+        Cause:   cause,
+        Payload: payload,
+        manager: sm,
+    }
+}
+
+// commit 在工作副本上依次执行各转换的出口、转换和入口动作，写入日志后提交
+// 动作执行期间不持有锁，任一动作失败时已提交的配置保持不变
+func (sm *StateManager) commit(active map[State]bool, history map[State][]State, transitions []*Transition, cause Cause, payload interface{}) error {
+    sm.mu.RLock()
+    journal := sm.journal
+    sm.mu.RUnlock()
+
+    events := make([]TransitionEvent, 0, len(transitions))
+    entries := make([]JournalEntry, 0, len(transitions))
+    for _, tr := range transitions {
+        if err := sm.execute(active, history, tr, cause, payload); err != nil {
+            return err
+        }
+        evt := TransitionEvent{
+            Machine:   sm.machine.Name(),
+            From:      tr.From,
+            To:        tr.To,
+            Event:     tr.Event,
+            Cause:     cause,
+            Payload:   payload,
+            Timestamp: time.Now(),
+        }
+        events = append(events, evt)
+        if journal != nil {
+            entries = append(entries, sm.entryFor(evt, active, history))
+        }
+    }
+
+    // 先以一个批次写日志再提交，保证已提交的转换均有记录
+    if len(entries) > 0 {
+        if _, err := journal.AppendBatch(context.Background(), entries); err != nil {
+            return err
+        }
+    }
+
+    sm.mu.Lock()
+    sm.active = active
+    sm.history = history
+    listeners := append([]Listener(nil), sm.listeners...)
+    sm.mu.Unlock()
+
+    for _, evt := range events {
+        for _, l := range listeners {
+            l(evt)
+        }
+    }
+    return nil
+}
+
+// entryFor 构造转换日志条目
+func (sm *StateManager) entryFor(evt TransitionEvent, active map[State]bool, history map[State][]State) JournalEntry {
+    recorded := make(map[State][]State, len(history))
+    for s, states := range history {
+        recorded[s] = append([]State(nil), states...)
+    }
+    return JournalEntry{
+        Timestamp: evt.Timestamp,
+        Subject:   sm.subject,
+        Kind:      EntryTransition,
+        Machine:   evt.Machine,
+        From:      evt.From,
+        To:        evt.To,
+        Event:     evt.Event,
+        Leaves:    sm.leaves(active),
+        History:   recorded,
+        Cause:     evt.Cause.Reason,
+        Actor:     evt.Cause.Actor,
+    }
+}
+
+// Replay 从挂接的日志重放转换，重建活动配置
+// 重放总是自管理器的初始配置开始，成功后替换当前配置与历史；
+// 重放只恢复配置，不执行守卫与动作，也不通知监听器；写入不完整的批次被跳过
+func (sm *StateManager) Replay(ctx context.Context) error {
+    return sm.process(func() error {
+        return sm.replay(ctx)
+    })
+}
+
+// replay 按日志条目重建活动配置
+func (sm *StateManager) replay(ctx context.Context) error {
+    sm.mu.RLock()
+    journal, subject := sm.journal, sm.subject
+    sm.mu.RUnlock()
+
+    if journal == nil {
+        return ErrNoJournal
+    }
+
+    entries, err := journal.Entries(ctx, subject, 0)
+    if err != nil {
+        return err
+    }
+
+    // 写入中途失败的批次未被提交，重放时跳过
+    active, history := sm.configFor(sm.initial), make(map[State][]State)
+    for _, entry := range complete(entries) {
+        if entry.Kind != EntryTransition || entry.Machine != sm.machine.Name() {
+            continue
+        }
+        if !active[entry.From] || !sm.machine.CanTransit(entry.From, entry.To) {
+            return fmt.Errorf("%w: %s#%d: %v -> %v", ErrJournalMismatch, entry.Subject, entry.Sequence, entry.From, entry.To)
+        }
+
+        next := make(map[State]bool)
+        for _, leaf := range entry.Leaves {
+            if !sm.machine.HasState(leaf) {
+                return fmt.Errorf("%w: %s#%d: %v", ErrJournalMismatch, entry.Subject, entry.Sequence, leaf)
+            }
+            for _, s := range sm.machine.ancestors(leaf) {
+                next[s] = true
+            }
+        }
+        active = next
+
+        history = make(map[State][]State, len(entry.History))
+        for s, states := range entry.History {
+            history[s] = append([]State(nil), states...)
+        }
+    }
+
+    sm.mu.Lock()
+    sm.active = active
+    sm.history = history
+    sm.mu.Unlock()
+    return nil
+}
+
+// execute 在给定配置上执行单个转换
+func (sm *StateManager) execute(active map[State]bool, history map[State][]State, tr *Transition, cause Cause, payload interface{}) error {
+    tc := sm.contextFor(tr, cause, payload)
+    domain := sm.machine.domain(tr.From, tr.To)
+
+    // 自内向外退出作用域内的活动状态，并记录历史
+    exits := sm.exitSet(active, tr)
+    for _, s := range exits {
+        sm.recordHistory(active, history, s)
+    }
+    for _, s := range exits {
+        if spec := sm.machine.spec(s); spec.OnExit != nil {
+            if err := spec.OnExit(tc); err != nil {
+                return err
+            }
+        }
+        delete(active, s)
+    }
+
+    if tr.Action != nil {
+        if err := tr.Action(tc); err != nil {
+            return err
+        }
+    }
+
+    // 自外向内进入目标及其默认后代
+    for _, s := range sm.machine.entryOrder(tr.To, domain, history) {
+        if spec := sm.machine.spec(s); spec.OnEntry != nil {
+            if err := spec.OnEntry(tc); err != nil {
+                return err
+            }
+        }
+        active[s] = true
+    }
+    return nil
+}
+
+// exitSet 获取转换需退出的活动状态，自内向外排列
+func (sm *StateManager) exitSet(active map[State]bool, tr *Transition) []State {
+    domain := sm.machine.domain(tr.From, tr.To)
+    exits := make([]State, 0)
+    for s := range active {
+        if sm.machine.isDescendant(s, domain) {
+            exits = append(exits, s)
+        }
+    }
+    sort.Slice(exits, func(i, j int) bool {
+        return sm.machine.nodes[exits[i]].index > sm.machine.nodes[exits[j]].index
+    })
+    return exits
+}
+
+// recordHistory 记录复合状态离开前的子状态
+func (sm *StateManager) recordHistory(active map[State]bool, history map[State][]State, s State) {
+    spec := sm.machine.spec(s)
+    if spec.History == HistoryNone || len(sm.machine.nodes[s].children) == 0 {
+        return
+    }
+
+    recorded := make([]State, 0)
+    for _, d := range sm.ordered(active) {
+        if spec.History == HistoryShallow && sm.machine.parentOf(d) == s {
+            recorded = append(recorded, d)
+        } else if spec.History == HistoryDeep && sm.machine.isDescendant(d, s) {
+            recorded = append(recorded, d)
+        }
+    }
+    history[s] = recorded
+}
+
+// ordered 按层次先序排列状态集合
+func (sm *StateManager) ordered(set map[State]bool) []State {
+    states := make([]State, 0, len(set))
+    for _, s := range sm.machine.order {
+        if set[s] {
+            states = append(states, s)
+        }
+    }
+    return states
+}
+
+// leaves 获取活动叶子状态，按层次先序排列
+func (sm *StateManager) leaves(set map[State]bool) []State {
+    leaves := make([]State, 0, 1)
+    for _, s := range sm.ordered(set) {
+        isLeaf := true
+        for _, child := range sm.machine.nodes[s].children {
+            if set[child] {
+                isLeaf = false
+                break
+            }
+        }
+        if isLeaf {
+            leaves = append(leaves, s)
+        }
+    }
+    return leaves
+}
@@ -0,0 +1,105 @@
+// core/state/manager_test.go
+
+package state
+
+import (
+    "errors"
+    "sync"
+    "testing"
+)
+
+// chainDefinition 线性状态 S0 -> S1 -> S2，由事件 next 推进
+func chainDefinition() Definition {
+    return Definition{
+        Name:    "chain",
+        Initial: "S0",
+        States: []StateSpec{
+            {Name: "S0"},
+            {Name: "S1"},
+            {Name: "S2"},
+        },
+        Transitions: []Transition{
+            {From: "S0", To: "S1", Event: "next"},
+            {From: "S1", To: "S2", Event: "next"},
+        },
+    }
+}
+
+func TestQueuedFireFromEntryAction(t *testing.T) {
+    def := chainDefinition()
+    def.States[1].OnEntry = func(tc *TransitionContext) error {
+        return tc.Fire("next", nil)
+    }
+    m, err := NewMachine(def)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    sm := NewStateManager(m)
+    if err := sm.Fire("next", nil); err != nil {
+        t.Fatal(err)
+    }
+    if got := sm.Current(); got != "S2" {
+        t.Fatalf("current = %v, want S2", got)
+    }
+}
+
+func TestQueuedFireErrorIsReturned(t *testing.T) {
+    def := chainDefinition()
+    def.States[2].OnEntry = func(tc *TransitionContext) error {
+        return tc.Fire("missing", nil)
+    }
+    def.States[1].OnEntry = func(tc *TransitionContext) error {
+        return tc.Fire("next", nil)
+    }
+    m, err := NewMachine(def)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    sm := NewStateManager(m)
+    if err := sm.Fire("next", nil); !errors.Is(err, ErrNoTransition) {
+        t.Fatalf("err = %v, want ErrNoTransition", err)
+    }
+}
+
+func TestConcurrentCallersWaitAndGetTheirErrors(t *testing.T) {
+    entered := make(chan struct{})
+    release := make(chan struct{})
+    def := chainDefinition()
+    def.States[1].OnEntry = func(*TransitionContext) error {
+        close(entered)
+        <-release
+        return nil
+    }
+    m, err := NewMachine(def)
+    if err != nil {
+        t.Fatal(err)
+    }
+    sm := NewStateManager(m)
+
+    var wg sync.WaitGroup
+    wg.Add(1)
+    go func() {
+        defer wg.Done()
+        if err := sm.Fire("next", nil); err != nil {
+            t.Error(err)
+        }
+    }()
+    <-entered
+
+    result := make(chan error, 1)
+    go func() {
+        // S1 尚未提交，等待后自 S1 出发无法转向 S0
+        result <- sm.TransitTo("S0")
+    }()
+    close(release)
+    wg.Wait()
+
+    if err := <-result; !errors.Is(err, ErrInvalidTransition) {
+        t.Fatalf("err = %v, want ErrInvalidTransition", err)
+    }
+    if got := sm.Current(); got != "S1" {
+        t.Fatalf("current = %v, want S1", got)
+    }
+}
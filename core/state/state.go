@@ -6,99 +6,140 @@ import (
     "fmt"
 )
 
-// BaseState 基础状态
-type BaseState uint8
+// State 状态标识
+type State string
 
+// 基础状态
 const (
-    StateVoid BaseState = iota
-    StateInactive
-    StateActive
-    StatePaused
-    StateTerminated
+    StateVoid       State = "Void"
+    StateInactive   State = "Inactive"
+    StateActive     State = "Active"
+    StatePaused     State = "Paused"
+    StateTerminated State = "Terminated"
 )
 
-// LifecycleState 生命周期状态
-type LifecycleState uint8
+// 生命周期状态
+const (
+    StageOrigin  State = "Origin"
+    StageBirth   State = "Birth"
+    StageGrowth  State = "Growth"
+    StagePeak    State = "Peak"
+    StageDecline State = "Decline"
+    StageEnd     State = "End"
+    StageReturn  State = "Return"
+)
+
+// 基础状态机事件
+const (
+    EventInitialize Event = "initialize"
+    EventActivate   Event = "activate"
+    EventDeactivate Event = "deactivate"
+    EventPause      Event = "pause"
+    EventResume     Event = "resume"
+    EventTerminate  Event = "terminate"
+    EventReset      Event = "reset"
+)
 
+// 生命周期状态机事件
 const (
-    StageOrigin LifecycleState = iota
-    StageBirth
-    StageGrowth
-    StagePeak
-    StageDecline
-    StageEnd
-    StageReturn
+    EventAdvance Event = "advance"
+    EventDecay   Event = "decay"
 )
 
-// 新增状态管理器
-type StateManager struct {
-    baseState      BaseState
-    lifecycleState LifecycleState
-    mu            sync.RWMutex
+// String 获取状态的字符串表示
+func (s State) String() string {
+    if s == "" {
+        return "Unknown"
+    }
+    return string(s)
+}
+
+// BaseDefinition 基础状态机声明
+func BaseDefinition() Definition {
+    return Definition{
+        Name:    "base",
+        Initial: StateVoid,
+        States: []StateSpec{
+            {Name: StateVoid},
+            {Name: StateInactive},
+            {Name: StateActive},
+            {Name: StatePaused},
+            {Name: StateTerminated},
+        },
+        Transitions: []Transition{
+            {From: StateVoid, To: StateInactive, Event: EventInitialize},
+            {From: StateInactive, To: StateActive, Event: EventActivate},
+            {From: StateInactive, To: StateVoid, Event: EventReset},
+            {From: StateActive, To: StatePaused, Event: EventPause},
+            {From: StateActive, To: StateInactive, Event: EventDeactivate},
+            {From: StatePaused, To: StateActive, Event: EventResume},
+            {From: StateVoid, To: StateTerminated, Event: EventTerminate},
+            {From: StateInactive, To: StateTerminated, Event: EventTerminate},
+            {From: StateActive, To: StateTerminated, Event: EventTerminate},
+            {From: StatePaused, To: StateTerminated, Event: EventTerminate},
+            {From: StateTerminated, To: StateVoid, Event: EventReset}, // 循环返回虚无
+        },
+    }
+}
+
+// LifecycleDefinition 生命周期状态机声明
+func LifecycleDefinition() Definition {
+    return Definition{
+        Name:    "lifecycle",
+        Initial: StateVoid,
+        States: []StateSpec{
+            {Name: StateVoid},
+            {Name: StageOrigin},
+            {Name: StageBirth},
+            {Name: StageGrowth},
+            {Name: StagePeak},
+            {Name: StageDecline},
+            {Name: StageEnd},
+            {Name: StageReturn},
+        },
+        Transitions: []Transition{
+            {From: StateVoid, To: StageOrigin, Event: EventAdvance},
+            {From: StageOrigin, To: StageBirth, Event: EventAdvance},
+            {From: StageBirth, To: StageGrowth, Event: EventAdvance},
+            {From: StageGrowth, To: StagePeak, Event: EventAdvance},
+            {From: StageGrowth, To: StageDecline, Event: EventDecay},
+            {From: StagePeak, To: StageDecline, Event: EventAdvance},
+            {From: StageDecline, To: StageEnd, Event: EventAdvance},
+            {From: StageEnd, To: StageReturn, Event: EventAdvance},
+            {From: StageReturn, To: StateVoid, Event: EventAdvance}, // 生命周期结束回到虚无
+        },
+    }
 }
 
-// StateTransitionMap 定义所有可能的状态转换
-var StateTransitionMap = map[State][]State{
-    // 基础状态转换
-    StateVoid:      {StateInactive, StageOrigin},  // 可以转向基础激活态或生命周期起源
-    StateInactive:  {StateActive},
-    StateActive:    {StatePaused, StateTerminated},
-    StatePaused:    {StateActive, StateTerminated},
-    StateTerminated: {StateVoid},  // 循环返回虚无
+var (
+    baseMachine      = MustNewMachine(BaseDefinition())
+    lifecycleMachine = MustNewMachine(LifecycleDefinition())
+)
+
+// BaseMachine 获取共享的基础状态机
+func BaseMachine() *Machine {
+    return baseMachine
+}
 
-    // 生命周期状态转换（从原 lifecycle_state.go 迁移）
-    StageOrigin:    {StageBirth},
-    StageBirth:     {StageGrowth},
-    StageGrowth:    {StagePeak, StageDecline},
-    StagePeak:      {StageDecline},
-    StageDecline:   {StageEnd},
-    StageEnd:       {StageReturn},
-    StageReturn:    {StateVoid},  // 生命周期结束回到虚无
+// LifecycleMachine 获取共享的生命周期状态机
+func LifecycleMachine() *Machine {
+    return lifecycleMachine
 }
 
-// ValidateTransition 验证状态转换是否有效
+// ValidateTransition 验证状态转换是否在基础或生命周期状态机中声明
 func ValidateTransition(current, next State) error {
-    validNextStates, exists := StateTransitionMap[current]
-    if !exists {
-        return fmt.Errorf("invalid current state: %v", current)
-    }
-    
-    for _, validState := range validNextStates {
-        if next == validState {
+    for _, m := range []*Machine{baseMachine, lifecycleMachine} {
+        if m.HasState(current) && m.CanTransit(current, next) {
             return nil
         }
     }
-    return fmt.Errorf("invalid state transition from %v to %v", current, next)
+    if !baseMachine.HasState(current) && !lifecycleMachine.HasState(current) {
+        return fmt.Errorf("%w: %v", ErrUnknownState, current)
+    }
+    return fmt.Errorf("%w: %v -> %v", ErrInvalidTransition, current, next)
 }
 
 // GetStateName 获取状态的字符串表示
 func GetStateName(s State) string {
-    switch s {
-    case StateVoid:
-        return "Void"
-    case StateInactive:
-        return "Inactive"
-    case StateActive:
-        return "Active"
-    case StatePaused:
-        return "Paused"
-    case StateTerminated:
-        return "Terminated"
-    case StageOrigin:
-        return "Origin"
-    case StageBirth:
-        return "Birth"
-    case StageGrowth:
-        return "Growth"
-    case StagePeak:
-        return "Peak"
-    case StageDecline:
-        return "Decline"
-    case StageEnd:
-        return "End"
-    case StageReturn:
-        return "Return"
-    default:
-        return "Unknown"
-    }
+    return s.String()
 }
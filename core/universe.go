@@ -4,22 +4,56 @@ package core
 
 import (
     "context"
+    "errors"
+    "fmt"
+    "math/rand"
     "sync"
     "time"
+
+    "github.com/Corphon/daoframe/core/clock"
+)
+
+// 宇宙错误
+var (
+    ErrInvalidPhase     = errors.New("invalid universe phase")
+    ErrUniverseEvolving = errors.New("universe is already evolving")
 )
 
+// DefaultTickInterval 万物生成的默认间隔
+const DefaultTickInterval = time.Millisecond * 100
+
 // Phase 表示宇宙演化阶段
 type Phase uint8
 
 const (
-    PhaseWuJi   Phase = iota // 无极：混沌未分
-    PhaseTaiJi              // 太极：道生一
-    PhaseYinYang            // 阴阳：一生二
-    PhaseTriad              // 三态：二生三
-    PhaseWuXing             // 五行：三生五
-    PhaseWanWu              // 万物：五生万
+    UniverseWuJi    Phase = iota // 无极：混沌未分
+    UniverseTaiJi                // 太极：道生一
+    UniverseYinYang              // 阴阳：一生二
+    UniverseTriad                // 三态：二生三
+    UniverseWuXing               // 五行：三生五
+    UniverseWanWu                // 万物：五生万
 )
 
+// String 获取阶段名称
+func (p Phase) String() string {
+    switch p {
+    case UniverseWuJi:
+        return "WuJi"
+    case UniverseTaiJi:
+        return "TaiJi"
+    case UniverseYinYang:
+        return "YinYang"
+    case UniverseTriad:
+        return "Triad"
+    case UniverseWuXing:
+        return "WuXing"
+    case UniverseWanWu:
+        return "WanWu"
+    default:
+        return fmt.Sprintf("Phase(%d)", uint8(p))
+    }
+}
+
 // Element 表示基本元素
 type Element struct {
     mu       sync.RWMutex
@@ -29,6 +63,34 @@ type Element struct {
     phase    Phase    // 所处阶段
 }
 
+// Yin 获取阴性比例
+func (e *Element) Yin() float64 {
+    e.mu.RLock()
+    defer e.mu.RUnlock()
+    return e.yin
+}
+
+// Yang 获取阳性比例
+func (e *Element) Yang() float64 {
+    e.mu.RLock()
+    defer e.mu.RUnlock()
+    return e.yang
+}
+
+// Energy 获取能量水平
+func (e *Element) Energy() float64 {
+    e.mu.RLock()
+    defer e.mu.RUnlock()
+    return e.energy
+}
+
+// Phase 获取元素生成时所处的演化阶段
+func (e *Element) Phase() Phase {
+    e.mu.RLock()
+    defer e.mu.RUnlock()
+    return e.phase
+}
+
 // Universe 表示宇宙整体
 type Universe struct {
     mu          sync.RWMutex
@@ -37,59 +99,146 @@ type Universe struct {
     elements    []*Element
     triad       *Triad      // 三态系统
     wuxing      *WuXing     // 五行系统
-    observers   []Observer
+    subscribers []*Subscription
     done        chan struct{}
+    evolving    bool // Evolve 已启动万物生成
+    closeOnce   sync.Once
+
+    rng         *rand.Rand    // 演化使用的随机源
+    source      *countingSource
+    clock       clock.Clock   // 演化使用的时钟
+    interval    time.Duration // 万物生成间隔
+    population  PopulationConfig // 万物种群管理
+    rules       GenerationRules  // 各阶段生成规则
+}
+
+// UniverseOption 宇宙选项
+type UniverseOption func(*Universe)
+
+// WithSeed 使用固定种子的随机源，相同种子的演化结果相同
+func WithSeed(seed int64) UniverseOption {
+    return func(u *Universe) {
+        u.source = newCountingSource(seed)
+    }
+}
+
+// WithUniverseClock 设置时钟
+func WithUniverseClock(c clock.Clock) UniverseOption {
+    return func(u *Universe) {
+        if c != nil {
+            u.clock = c
+        }
+    }
+}
+
+// WithTickInterval 设置万物生成间隔
+func WithTickInterval(interval time.Duration) UniverseOption {
+    return func(u *Universe) {
+        if interval > 0 {
+            u.interval = interval
+        }
+    }
 }
 
 // NewUniverse 创建新的宇宙实例，从无极开始
-func NewUniverse(ctx context.Context) *Universe {
-    return &Universe{
+// 未指定时钟时使用 ctx 携带的时钟
+func NewUniverse(ctx context.Context, opts ...UniverseOption) *Universe {
+    u := &Universe{
         ctx:       ctx,
-        phase:     PhaseWuJi,
+        phase:     UniverseWuJi,
         elements:  make([]*Element, 0),
-        observers: make([]Observer, 0),
+        subscribers: make([]*Subscription, 0),
         done:      make(chan struct{}),
+        clock:     clock.FromContext(ctx),
+        interval:  DefaultTickInterval,
+        population: DefaultPopulationConfig(),
+        rules:      DefaultGenerationRules(),
+    }
+    for _, opt := range opts {
+        opt(u)
     }
+    if u.source == nil {
+        u.source = newCountingSource(time.Now().UnixNano())
+    }
+    u.rng = rand.New(u.source)
+    return u
 }
 
-// Evolve 开始宇宙演化
-func (u *Universe) Evolve() error {
-    u.mu.Lock()
-    defer u.mu.Unlock()
+// countingSource 记录抽取次数的随机源，快照据此恢复随机序列
+type countingSource struct {
+    src   rand.Source64
+    seed  int64
+    draws uint64
+}
 
-    steps := []struct {
-        name string
-        fn   func() error
-    }{
-        {"太极生成", u.generateTaiJi},
-        {"阴阳分化", u.generateYinYang},
-        {"三态演化", u.generateTriad},
-        {"五行生成", u.generateWuXing},
+// newCountingSource 创建随机源
+func newCountingSource(seed int64) *countingSource {
+    return &countingSource{
+        src:  rand.NewSource(seed).(rand.Source64),
+        seed: seed,
     }
+}
 
-    for _, step := range steps {
-        if err := step.fn(); err != nil {
-            return fmt.Errorf("%s failed: %w", step.name, err)
-        }
-    }
-    
-    // 1. 无极 -> 太极（道生一）
-    if err := u.generateTaiJi(); err != nil {
-        return err
-    }
+func (s *countingSource) Int63() int64 {
+    s.draws++
+    return s.src.Int63()
+}
 
-    // 2. 太极 -> 阴阳（一生二）
-    if err := u.generateYinYang(); err != nil {
-        return err
+func (s *countingSource) Uint64() uint64 {
+    s.draws++
+    return s.src.Uint64()
+}
+
+func (s *countingSource) Seed(seed int64) {
+    s.src.Seed(seed)
+    s.seed = seed
+    s.draws = 0
+}
+
+// skip 丢弃 n 次抽取
+func (s *countingSource) skip(n uint64) {
+    for i := uint64(0); i < n; i++ {
+        s.Uint64()
     }
+}
 
-    // 3. 阴阳 -> 三态（二生三）
-    if err := u.generateTriad(); err != nil {
-        return err
+// Phase 获取当前演化阶段
+func (u *Universe) Phase() Phase {
+    u.mu.RLock()
+    defer u.mu.RUnlock()
+    return u.phase
+}
+
+// Stop 停止万物生成并关闭全部订阅，尚未投递的事件被丢弃
+func (u *Universe) Stop() {
+    u.closeOnce.Do(func() {
+        u.mu.Lock()
+        close(u.done)
+        subs := u.subscribers
+        u.subscribers = nil
+        u.mu.Unlock()
+
+        for _, s := range subs {
+            s.close()
+        }
+    })
+}
+
+// Evolve 开始宇宙演化，五行生成后按间隔持续生成万物
+// 已完成的阶段被跳过，由快照恢复的宇宙可直接调用 Evolve 继续生成万物
+func (u *Universe) Evolve() error {
+    u.mu.Lock()
+    if u.evolving {
+        u.mu.Unlock()
+        return ErrUniverseEvolving
     }
+    u.evolving = true
+    u.mu.Unlock()
 
-    // 4. 三态 -> 五行（三生五）
-    if err := u.generateWuXing(); err != nil {
+    if err := u.evolvePhases(); err != nil {
+        u.mu.Lock()
+        u.evolving = false
+        u.mu.Unlock()
         return err
     }
 
@@ -99,59 +248,74 @@ func (u *Universe) Evolve() error {
     return nil
 }
 
-// generateTaiJi 实现"道生一"：从无极生成太极
-func (u *Universe) generateTaiJi() error {
+// evolvePhases 依次完成无极到五行中尚未完成的演化
+func (u *Universe) evolvePhases() error {
+    events, err := u.evolvePhasesLocked()
+    u.publish(events)
+    return err
+}
+
+// evolvePhasesLocked 在锁内完成演化，返回阶段变化事件
+func (u *Universe) evolvePhasesLocked() ([]UniverseEvent, error) {
     u.mu.Lock()
     defer u.mu.Unlock()
 
-    if u.phase != PhaseWuJi {
-        return ErrInvalidPhase
+    if err := u.rules.Validate(); err != nil {
+        return nil, err
     }
 
-    // 创建原始元素，能量完全平衡
-    primordial := &Element{
-        yin:    0.5,
-        yang:   0.5,
-        energy: 1.0,
-        phase:  PhaseTaiJi,
+    steps := []struct {
+        name string
+        to   Phase
+        fn   func() error
+    }{
+        {"太极生成", UniverseTaiJi, u.generateTaiJi},     // 1. 无极 -> 太极（道生一）
+        {"阴阳分化", UniverseYinYang, u.generateYinYang}, // 2. 太极 -> 阴阳（一生二）
+        {"三态演化", UniverseTriad, u.generateTriad},     // 3. 阴阳 -> 三态（二生三）
+        {"五行生成", UniverseWuXing, u.generateWuXing},   // 4. 三态 -> 五行（三生五）
+    }
+
+    events := make([]UniverseEvent, 0, len(steps))
+    for _, step := range steps {
+        // 跳过已完成的阶段
+        if u.phase >= step.to {
+            continue
+        }
+        from := u.phase
+        if err := step.fn(); err != nil {
+            return events, fmt.Errorf("%s failed: %w", step.name, err)
+        }
+        events = append(events, PhaseChangeEvent{From: from, To: u.phase, Time: u.clock.Now()})
+    }
+    return events, nil
+}
+
+// generateTaiJi 实现"道生一"：从无极生成太极，调用前须持有锁
+func (u *Universe) generateTaiJi() error {
+    if u.phase != UniverseWuJi {
+        return ErrInvalidPhase
     }
+
+    // 创建原始元素
+    primordial := u.rules.TaiJi.element(UniverseTaiJi)
     u.elements = append(u.elements, primordial)
-    u.phase = PhaseTaiJi
-    
+    u.phase = UniverseTaiJi
+
     return nil
 }
 
-// generateYinYang 实现"一生二"：太极分化为阴阳
+// generateYinYang 实现"一生二"：太极分化为阴阳，调用前须持有锁
 func (u *Universe) generateYinYang() error {
-    u.mu.Lock()
-    defer u.mu.Unlock()
-
-    if u.phase != PhaseTaiJi {
+    if u.phase != UniverseTaiJi {
         return ErrInvalidPhase
     }
 
     // 从原始元素分化出阴阳
-    original := u.elements[0]
-    
-    // 创建阴元素
-    yin := &Element{
-        yin:    0.8,
-        yang:   0.2,
-        energy: original.energy / 2,
-        phase:  PhaseYinYang,
-    }
-    
-    // 创建阳元素
-    yang := &Element{
-        yin:    0.2,
-        yang:   0.8,
-        energy: original.energy / 2,
-        phase:  PhaseYinYang,
+    for _, rule := range u.rules.YinYang {
+        u.elements = append(u.elements, rule.element(UniverseYinYang))
     }
+    u.phase = UniverseYinYang
 
-    u.elements = append(u.elements, yin, yang)
-    u.phase = PhaseYinYang
-    
     return nil
 }
 
@@ -162,116 +326,98 @@ type Triad struct {
     balance   *Element // 中和
 }
 
-// generateTriad 实现"二生三"：阴阳相互作用产生三态
+// generateTriad 实现"二生三"：阴阳相互作用产生三态，调用前须持有锁
 func (u *Universe) generateTriad() error {
-    u.mu.Lock()
-    defer u.mu.Unlock()
-
-    if u.phase != PhaseYinYang {
+    if u.phase != UniverseYinYang {
         return ErrInvalidPhase
     }
 
     // 从阴阳相互作用生成三态
+    rules := u.rules.Triad
     u.triad = &Triad{
-        minorYin: &Element{
-            yin:    0.7,
-            yang:   0.3,
-            energy: 1.0,
-            phase:  PhaseTriad,
-        },
-        minorYang: &Element{
-            yin:    0.3,
-            yang:   0.7,
-            energy: 1.0,
-            phase:  PhaseTriad,
-        },
-        balance: &Element{
-            yin:    0.5,
-            yang:   0.5,
-            energy: 1.0,
-            phase:  PhaseTriad,
-        },
+        minorYin:  rules[0].element(UniverseTriad),
+        minorYang: rules[1].element(UniverseTriad),
+        balance:   rules[2].element(UniverseTriad),
     }
 
-    u.elements = append(u.elements, 
-        u.triad.minorYin, 
-        u.triad.minorYang, 
+    u.elements = append(u.elements,
+        u.triad.minorYin,
+        u.triad.minorYang,
         u.triad.balance,
     )
-    u.phase = PhaseTriad
-    
+    u.phase = UniverseTriad
+
     return nil
 }
 
-// generateWuXing 实现"三生五"：三态演化为五行
+// generateWuXing 实现"三生五"：三态演化为五行，调用前须持有锁
 func (u *Universe) generateWuXing() error {
-    u.mu.Lock()
-    defer u.mu.Unlock()
-
-    if u.phase != PhaseTriad {
+    if u.phase != UniverseTriad {
         return ErrInvalidPhase
     }
 
-    // 从三态演化出五行
-    wood := &Element{yin: 0.4, yang: 0.6, energy: 1.0, phase: PhaseWuXing}
-    fire := &Element{yin: 0.2, yang: 0.8, energy: 1.0, phase: PhaseWuXing}
-    earth := &Element{yin: 0.5, yang: 0.5, energy: 1.0, phase: PhaseWuXing}
-    metal := &Element{yin: 0.6, yang: 0.4, energy: 1.0, phase: PhaseWuXing}
-    water := &Element{yin: 0.8, yang: 0.2, energy: 1.0, phase: PhaseWuXing}
+    // 从三态演化出五行，按相生次序排列
+    elements := make([]*Element, len(u.rules.WuXing))
+    for i, rule := range u.rules.WuXing {
+        elements[i] = rule.element(UniverseWuXing)
+    }
 
-    u.wuxing = NewWuXing([]*Element{wood, fire, earth, metal, water})
-    u.elements = append(u.elements, wood, fire, earth, metal, water)
-    u.phase = PhaseWuXing
+    u.wuxing = newWuXing(elements, u.rules.WanWu)
+    u.elements = append(u.elements, elements...)
+    u.phase = UniverseWuXing
 
     return nil
 }
 
 // generateWanWu 实现"五生万物"：持续的演化过程
 func (u *Universe) generateWanWu() {
-    ticker := time.NewTicker(time.Millisecond * 100)
+    ticker := u.clock.NewTicker(u.interval)
     defer ticker.Stop()
 
     for {
         select {
         case <-u.done:
             return
-        case <-ticker.C:
-            u.mu.Lock()
-            if u.phase == PhaseWuXing {
-                // 通过五行相互作用生成新元素
-                if newElement := u.wuxing.Interact(); newElement != nil {
-                    newElement.phase = PhaseWanWu
-                    u.elements = append(u.elements, newElement)
-                    // 通知观察者
-                    u.notifyObservers(EventNewElement, newElement)
-                }
-            }
-            u.mu.Unlock()
+        case <-ticker.C():
+            u.tick()
         }
     }
 }
 
-// 观察者相关定义
-type EventType uint8
-
-const (
-    EventNewElement EventType = iota
-    EventPhaseChange
-    EventInteraction
-)
+// tick 执行一次万物生成与种群管理，返回新生成的元素
+// 事件在释放锁之后发布
+func (u *Universe) tick() *Element {
+    u.mu.Lock()
+    newElement, events := u.step()
+    u.mu.Unlock()
 
-type Observer interface {
-    OnEvent(eventType EventType, data interface{})
+    u.publish(events)
+    return newElement
 }
 
-func (u *Universe) AddObserver(observer Observer) {
-    u.mu.Lock()
-    defer u.mu.Unlock()
-    u.observers = append(u.observers, observer)
-}
+// step 生成万物并管理种群，调用前须持有锁
+func (u *Universe) step() (*Element, []UniverseEvent) {
+    if u.phase < UniverseWuXing || u.wuxing == nil {
+        return nil, nil
+    }
+    now := u.clock.Now()
+    events := make([]UniverseEvent, 0)
+
+    // 通过五行相互作用生成新元素
+    mother, child, newElement := u.wuxing.interact(u.rng)
+    if newElement != nil {
+        newElement.phase = UniverseWanWu
+        u.elements = append(u.elements, newElement)
+        events = append(events,
+            InteractionEvent{Mother: mother, Child: child, Result: newElement, Time: now},
+            NewElementEvent{Element: newElement, Time: now},
+        )
+    }
 
-func (u *Universe) notifyObservers(eventType EventType, data interface{}) {
-    for _, observer := range u.observers {
-        observer.OnEvent(eventType, data)
+    // 衰减、合并与淘汰
+    for _, removal := range u.maintainPopulation() {
+        removal.Time = now
+        events = append(events, removal)
     }
+    return newElement, events
 }
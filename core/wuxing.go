@@ -0,0 +1,72 @@
+// core/wuxing.go
+
+package core
+
+import (
+    "math/rand"
+)
+
+// WuXing 宇宙的五行系统，元素按木火土金水的相生次序排列
+type WuXing struct {
+    elements []*Element
+    rule     WanWuRule
+}
+
+// NewWuXing 以默认的万物生成参数创建五行系统
+func NewWuXing(elements []*Element) *WuXing {
+    return newWuXing(elements, DefaultGenerationRules().WanWu)
+}
+
+// newWuXing 以指定的万物生成参数创建五行系统
+func newWuXing(elements []*Element, rule WanWuRule) *WuXing {
+    return &WuXing{elements: elements, rule: rule}
+}
+
+// Elements 获取五行元素
+func (wx *WuXing) Elements() []*Element {
+    return append([]*Element(nil), wx.elements...)
+}
+
+// Interact 随机选取一对相生的母子元素，生成兼具二者性质的新元素
+// 新元素的能量取自母元素，母元素能量不足时返回 nil
+func (wx *WuXing) Interact(rng *rand.Rand) *Element {
+    _, _, result := wx.interact(rng)
+    return result
+}
+
+// interact 执行一次相互作用，同时返回参与的母子元素
+func (wx *WuXing) interact(rng *rand.Rand) (mother, child, result *Element) {
+    n := len(wx.elements)
+    if n == 0 {
+        return nil, nil, nil
+    }
+    i := rng.Intn(n)
+    mother = wx.elements[i]
+    child = wx.elements[(i+1)%n]
+
+    child.mu.RLock()
+    childYin := child.yin
+    child.mu.RUnlock()
+
+    mother.mu.Lock()
+    defer mother.mu.Unlock()
+
+    if mother.energy < wx.rule.MinEnergy {
+        return mother, child, nil
+    }
+    energy := mother.energy * wx.rule.Transfer
+    mother.energy -= energy
+
+    yin := (mother.yin+childYin)/2 + (rng.Float64()*2-1)*wx.rule.Jitter
+    if yin < 0 {
+        yin = 0
+    } else if yin > 1 {
+        yin = 1
+    }
+
+    return mother, child, &Element{
+        yin:    yin,
+        yang:   1 - yin,
+        energy: energy,
+    }
+}
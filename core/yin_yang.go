@@ -0,0 +1,39 @@
+// core/yin_yang.go
+
+package core
+
+import (
+    "context"
+    "os"
+    "time"
+)
+
+// YinYang 阴阳二气，由太极生成，与太极共享本源
+type YinYang struct {
+    taiji *TaiJi
+}
+
+// NewYinYang 由太极生成阴阳，对应"一生二"
+func NewYinYang(t *TaiJi) *YinYang {
+    return &YinYang{taiji: t}
+}
+
+// Context 获取所属太极的本源上下文，模型应以此构造以共享时钟
+func (yy *YinYang) Context() *DaoContext {
+    return yy.taiji.Context()
+}
+
+// Shutdown 按阶段优雅关闭其所属的太极
+func (yy *YinYang) Shutdown(ctx context.Context) error {
+    return yy.taiji.Shutdown(ctx)
+}
+
+// ShutdownOnSignal 收到系统信号后关闭其所属的太极
+func (yy *YinYang) ShutdownOnSignal(ctx context.Context, timeout time.Duration, signals ...os.Signal) error {
+    return yy.taiji.ShutdownOnSignal(ctx, timeout, signals...)
+}
+
+// Done 返回关闭完成时关闭的通道
+func (yy *YinYang) Done() <-chan struct{} {
+    return yy.taiji.Done()
+}
@@ -1,4 +1,21 @@
-package basic
+// model/bagua.go
+
+package model
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "sync"
+
+    "github.com/Corphon/daoframe/core"
+)
+
+var (
+    ErrInvalidTrigram     = errors.New("无效的卦")
+    ErrInvalidArrangement = errors.New("无效的八卦排列")
+    ErrInvalidFlowConfig  = errors.New("无效的八卦流动配置")
+)
 
 type Trigram uint8
 
@@ -13,13 +30,83 @@ const (
     TrigramDui                 // 兑 ☱
 )
 
+// trigramCount 卦的数量
+const trigramCount = 8
+
+// trigramLines 八卦的爻，自下而上
+var trigramLines = map[Trigram][3]Line{
+    TrigramQian: {LineYang, LineYang, LineYang},
+    TrigramKun:  {LineYin, LineYin, LineYin},
+    TrigramZhen: {LineYang, LineYin, LineYin},
+    TrigramXun:  {LineYin, LineYang, LineYang},
+    TrigramKan:  {LineYin, LineYang, LineYin},
+    TrigramLi:   {LineYang, LineYin, LineYang},
+    TrigramGen:  {LineYin, LineYin, LineYang},
+    TrigramDui:  {LineYang, LineYang, LineYin},
+}
+
+// Lines 获取卦的爻，自下而上
+func (t Trigram) Lines() [3]Line {
+    return trigramLines[t]
+}
+
+// TrigramOf 由自下而上的三爻获取卦
+func TrigramOf(lines [3]Line) Trigram {
+    for t, l := range trigramLines {
+        if l == lines {
+            return t
+        }
+    }
+    return TrigramQian
+}
+
+// FlowMatrix 卦间能量流动矩阵，[i][j] 为卦 i 流向卦 j 的能量
+type FlowMatrix [trigramCount][trigramCount]float64
+
+// BaGuaConfig 八卦能量网络配置
+type BaGuaConfig struct {
+    Arrangement  Arrangement
+    AdjacentRate float64 // 相邻卦之间每周期按能量差由强向弱流动的比例
+    OppositeRate float64 // 相对卦之间每周期交感的比例
+    ElementBias  float64 // 五行修正：相生方向流动增加此比例，相克方向减少此比例
+}
+
+// DefaultBaGuaConfig 默认八卦配置
+func DefaultBaGuaConfig() BaGuaConfig {
+    return BaGuaConfig{
+        Arrangement:  ArrangementKingWen,
+        AdjacentRate: 0.1,
+        OppositeRate: 0.05,
+        ElementBias:  0.5,
+    }
+}
+
+// Validate 校验配置，每卦各方向的流动比例之和不得超过 1/2，以免一个周期内能量反转
+func (c BaGuaConfig) Validate() error {
+    if !c.Arrangement.Valid() {
+        return ErrInvalidArrangement
+    }
+    if c.AdjacentRate < 0 || c.OppositeRate < 0 || c.ElementBias < 0 || c.ElementBias > 1 {
+        return fmt.Errorf("%w: 比例不能为负，五行修正不能超过 1", ErrInvalidFlowConfig)
+    }
+    if 2*c.AdjacentRate*(1+c.ElementBias)+c.OppositeRate > 0.5 {
+        return fmt.Errorf("%w: 流动比例过大", ErrInvalidFlowConfig)
+    }
+    return nil
+}
+
 // BaGua 八卦系统
+// 八卦按排列分布于八方，构成能量网络：相邻卦按能量差扩散并受五行生克修正，相对卦交感
 type BaGua struct {
     mu            sync.RWMutex
     trigrams      map[Trigram]*TrigramState
-    energyFlows   map[Trigram][]EnergyFlow
-    interactions  map[Trigram]map[Trigram]float64
+    energyFlows   map[Trigram][]EnergyFlow         // 最近一个周期各卦流出的能量
+    interactions  map[Trigram]map[Trigram]float64 // 卦间的耦合系数
     ctx           *core.DaoContext
+    tree          *YinYangNode // 提供卦象能量的阴阳分化树
+    config        BaGuaConfig
+    flows         FlowMatrix // 最近一个周期的流动矩阵
+    ticks         uint64
 }
 
 // TrigramState 卦象状态
@@ -33,8 +120,332 @@ type TrigramState struct {
 
 // EnergyFlow 能量流动
 type EnergyFlow struct {
-    source    Trigram
-    target    Trigram
-    strength  float64
-    nature    Nature
+    Source    Trigram
+    Target    Trigram
+    Strength  float64
+    Nature    Nature // 流出卦的阴阳
+}
+
+// NewBaGua 创建八卦系统，各卦能量初始为 0.5
+func NewBaGua(ctx *core.DaoContext, config BaGuaConfig) (*BaGua, error) {
+    if err := config.Validate(); err != nil {
+        return nil, err
+    }
+
+    bg := &BaGua{
+        trigrams:    make(map[Trigram]*TrigramState),
+        energyFlows: make(map[Trigram][]EnergyFlow),
+        ctx:         ctx,
+    }
+    for t := Trigram(0); t < trigramCount; t++ {
+        attr := t.Attribute()
+        bg.trigrams[t] = &TrigramState{
+            trigram:   t,
+            energy:    0.5,
+            attribute: &attr,
+            element:   t.Element(),
+        }
+    }
+    bg.configure(config)
+    return bg, nil
+}
+
+// configure 按配置设置方位与耦合系数，调用前须持有锁或尚未共享
+func (bg *BaGua) configure(config BaGuaConfig) {
+    bg.config = config
+    for t, state := range bg.trigrams {
+        state.direction = config.Arrangement.Direction(t)
+    }
+
+    bg.interactions = make(map[Trigram]map[Trigram]float64, trigramCount)
+    for t := Trigram(0); t < trigramCount; t++ {
+        bg.interactions[t] = make(map[Trigram]float64)
+        dir := config.Arrangement.Direction(t)
+        for _, d := range dir.Neighbors() {
+            other := config.Arrangement.TrigramAt(d)
+            bg.interactions[t][other] = config.AdjacentRate * elementFactor(t.Element(), other.Element(), config.ElementBias)
+        }
+        bg.interactions[t][config.Arrangement.TrigramAt(dir.Opposite())] = config.OppositeRate
+    }
+}
+
+// elementFactor 五行对流动的修正
+func elementFactor(from, to Phase, bias float64) float64 {
+    switch {
+    case generates(from) == to:
+        return 1 + bias
+    case controls(from) == to:
+        return 1 - bias
+    default:
+        return 1
+    }
+}
+
+// SetConfig 更换配置，能量保持不变
+func (bg *BaGua) SetConfig(config BaGuaConfig) error {
+    if err := config.Validate(); err != nil {
+        return err
+    }
+    bg.mu.Lock()
+    defer bg.mu.Unlock()
+    bg.configure(config)
+    return nil
+}
+
+// SetArrangement 切换先天或后天排列
+func (bg *BaGua) SetArrangement(arrangement Arrangement) error {
+    bg.mu.RLock()
+    config := bg.config
+    bg.mu.RUnlock()
+
+    config.Arrangement = arrangement
+    return bg.SetConfig(config)
+}
+
+// Arrangement 获取当前排列
+func (bg *BaGua) Arrangement() Arrangement {
+    bg.mu.RLock()
+    defer bg.mu.RUnlock()
+    return bg.config.Arrangement
+}
+
+// Direction 获取卦在当前排列中的方位
+func (bg *BaGua) Direction(t Trigram) Direction {
+    bg.mu.RLock()
+    defer bg.mu.RUnlock()
+    return bg.config.Arrangement.Direction(t)
+}
+
+// Opposite 获取当前排列中与 t 相对的卦
+func (bg *BaGua) Opposite(t Trigram) Trigram {
+    bg.mu.RLock()
+    defer bg.mu.RUnlock()
+    a := bg.config.Arrangement
+    return a.TrigramAt(a.Direction(t).Opposite())
+}
+
+// Adjacent 获取当前排列中与 t 相邻的两卦
+func (bg *BaGua) Adjacent(t Trigram) [2]Trigram {
+    bg.mu.RLock()
+    defer bg.mu.RUnlock()
+    a := bg.config.Arrangement
+    neighbors := a.Direction(t).Neighbors()
+    return [2]Trigram{a.TrigramAt(neighbors[0]), a.TrigramAt(neighbors[1])}
+}
+
+// SetEnergy 设置卦的能量
+func (bg *BaGua) SetEnergy(t Trigram, energy float64) error {
+    if t >= trigramCount {
+        return ErrInvalidTrigram
+    }
+    if energy < 0 || math.IsNaN(energy) {
+        return fmt.Errorf("%w: 能量不能为负", ErrInvalidFlowConfig)
+    }
+    bg.mu.Lock()
+    defer bg.mu.Unlock()
+    bg.trigrams[t].energy = energy
+    return nil
+}
+
+// TotalEnergy 获取八卦能量总和
+func (bg *BaGua) TotalEnergy() float64 {
+    bg.mu.RLock()
+    defer bg.mu.RUnlock()
+
+    total := 0.0
+    for _, state := range bg.trigrams {
+        total += state.energy
+    }
+    return total
+}
+
+// Tick 执行一个周期的能量流动，返回流动矩阵
+// 流动量按周期开始时的能量计算后同时应用，总能量守恒；流动只作用于卦象状态，不写回分化树
+func (bg *BaGua) Tick() FlowMatrix {
+    bg.mu.Lock()
+    defer bg.mu.Unlock()
+
+    var energy [trigramCount]float64
+    for t, state := range bg.trigrams {
+        energy[t] = state.energy
+    }
+
+    var flows FlowMatrix
+    for from, targets := range bg.interactions {
+        for to, coupling := range targets {
+            if diff := energy[from] - energy[to]; diff > 0 {
+                flows[from][to] = coupling * diff
+            }
+        }
+    }
+
+    bg.energyFlows = make(map[Trigram][]EnergyFlow, trigramCount)
+    for from := Trigram(0); from < trigramCount; from++ {
+        for to := Trigram(0); to < trigramCount; to++ {
+            amount := flows[from][to]
+            if amount == 0 {
+                continue
+            }
+            bg.trigrams[from].energy -= amount
+            bg.trigrams[to].energy += amount
+            bg.energyFlows[from] = append(bg.energyFlows[from], EnergyFlow{
+                Source:   from,
+                Target:   to,
+                Strength: amount,
+                Nature:   from.Nature(),
+            })
+        }
+    }
+
+    bg.flows = flows
+    bg.ticks++
+    return flows
+}
+
+// ProcessEnergyFlows 执行一个周期的能量流动
+func (bg *BaGua) ProcessEnergyFlows() {
+    bg.Tick()
+}
+
+// Flows 获取最近一个周期的流动矩阵
+func (bg *BaGua) Flows() FlowMatrix {
+    bg.mu.RLock()
+    defer bg.mu.RUnlock()
+    return bg.flows
+}
+
+// OutgoingFlows 获取最近一个周期卦 t 流出的能量
+func (bg *BaGua) OutgoingFlows(t Trigram) []EnergyFlow {
+    bg.mu.RLock()
+    defer bg.mu.RUnlock()
+    return append([]EnergyFlow(nil), bg.energyFlows[t]...)
+}
+
+// Coupling 获取卦间的耦合系数矩阵，非相邻且非相对的卦为 0
+func (bg *BaGua) Coupling() FlowMatrix {
+    bg.mu.RLock()
+    defer bg.mu.RUnlock()
+
+    var m FlowMatrix
+    for from, targets := range bg.interactions {
+        for to, coupling := range targets {
+            m[from][to] = coupling
+        }
+    }
+    return m
+}
+
+// Ticks 获取已执行的周期数
+func (bg *BaGua) Ticks() uint64 {
+    bg.mu.RLock()
+    defer bg.mu.RUnlock()
+    return bg.ticks
+}
+
+// CalculateEnergyExchange 计算当前能量下一个周期内 source 与 target 的净交换量
+// 正值表示由 source 流向 target
+func (bg *BaGua) CalculateEnergyExchange(source, target Trigram) float64 {
+    bg.mu.RLock()
+    defer bg.mu.RUnlock()
+
+    if source >= trigramCount || target >= trigramCount || source == target {
+        return 0
+    }
+    diff := bg.trigrams[source].energy - bg.trigrams[target].energy
+    if diff >= 0 {
+        return bg.interactions[source][target] * diff
+    }
+    return bg.interactions[target][source] * diff
+}
+
+// GetTrigramAttributes 获取两卦的属性与关系
+func (bg *BaGua) GetTrigramAttributes(source, target Trigram) map[string]float64 {
+    bg.mu.RLock()
+    defer bg.mu.RUnlock()
+
+    attrs := make(map[string]float64)
+    if source >= trigramCount || target >= trigramCount {
+        return attrs
+    }
+
+    a := bg.config.Arrangement
+    src, dst := bg.trigrams[source], bg.trigrams[target]
+    attrs["source_energy"] = src.energy
+    attrs["target_energy"] = dst.energy
+    attrs["source_yang"] = src.attribute.Yang
+    attrs["target_yang"] = dst.attribute.Yang
+    attrs["coupling"] = bg.interactions[source][target]
+    attrs["element_factor"] = elementFactor(src.element, dst.element, bg.config.ElementBias)
+
+    attrs["opposite"] = 0
+    if a.Direction(source).Opposite() == a.Direction(target) {
+        attrs["opposite"] = 1
+    }
+    attrs["adjacent"] = 0
+    for _, d := range a.Direction(source).Neighbors() {
+        if d == a.Direction(target) {
+            attrs["adjacent"] = 1
+        }
+    }
+    return attrs
+}
+
+// BindTree 以阴阳分化树提供卦象能量
+// 绑定时同步一次能量，此后的周期在卦象状态上流动；需要重新取树中能量时调用 SyncEnergy
+func (bg *BaGua) BindTree(tree *YinYangNode) error {
+    if tree == nil {
+        return ErrNotDerived
+    }
+    for t := TrigramQian; t <= TrigramDui; t++ {
+        if _, err := tree.TrigramNode(t); err != nil {
+            return err
+        }
+    }
+
+    bg.mu.Lock()
+    defer bg.mu.Unlock()
+    bg.tree = tree
+    bg.syncEnergy()
+    return nil
+}
+
+// TrigramEnergy 获取卦象能量
+func (bg *BaGua) TrigramEnergy(t Trigram) float64 {
+    bg.mu.RLock()
+    defer bg.mu.RUnlock()
+
+    if state, exists := bg.trigrams[t]; exists {
+        return state.energy
+    }
+    return 0
+}
+
+// SyncEnergy 将分化树中的能量写入卦象状态，覆盖此前周期的流动结果
+func (bg *BaGua) SyncEnergy() {
+    bg.mu.Lock()
+    defer bg.mu.Unlock()
+    bg.syncEnergy()
+}
+
+// syncEnergy 同步分化树中的能量，调用前须持有锁
+func (bg *BaGua) syncEnergy() {
+    if bg.tree == nil {
+        return
+    }
+    if bg.trigrams == nil {
+        bg.trigrams = make(map[Trigram]*TrigramState)
+    }
+    for t := TrigramQian; t <= TrigramDui; t++ {
+        node, err := bg.tree.TrigramNode(t)
+        if err != nil {
+            continue
+        }
+        state, exists := bg.trigrams[t]
+        if !exists {
+            attr := t.Attribute()
+            state = &TrigramState{trigram: t, attribute: &attr, element: t.Element()}
+            bg.trigrams[t] = state
+        }
+        state.energy = node.Energy()
+    }
 }
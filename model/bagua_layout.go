@@ -0,0 +1,181 @@
+// model/bagua_layout.go
+
+package model
+
+import (
+    "fmt"
+)
+
+// Direction 方位，自北起顺时针排列
+type Direction uint8
+
+const (
+    DirectionNorth     Direction = iota // 北
+    DirectionNorthEast                  // 东北
+    DirectionEast                       // 东
+    DirectionSouthEast                  // 东南
+    DirectionSouth                      // 南
+    DirectionSouthWest                  // 西南
+    DirectionWest                       // 西
+    DirectionNorthWest                  // 西北
+)
+
+// directionCount 方位数量
+const directionCount = 8
+
+var directionNames = [directionCount]string{"北", "东北", "东", "东南", "南", "西南", "西", "西北"}
+
+// String 获取方位名称
+func (d Direction) String() string {
+    if d < directionCount {
+        return directionNames[d]
+    }
+    return fmt.Sprintf("Direction(%d)", uint8(d))
+}
+
+// Opposite 获取相对的方位
+func (d Direction) Opposite() Direction {
+    return (d + directionCount/2) % directionCount
+}
+
+// Neighbors 获取相邻的两个方位
+func (d Direction) Neighbors() [2]Direction {
+    return [2]Direction{
+        (d + directionCount - 1) % directionCount,
+        (d + 1) % directionCount,
+    }
+}
+
+// Arrangement 八卦方位排列
+type Arrangement uint8
+
+const (
+    ArrangementFuXi    Arrangement = iota // 伏羲先天八卦
+    ArrangementKingWen                    // 文王后天八卦
+)
+
+// arrangements 各排列中卦的方位
+var arrangements = map[Arrangement]map[Trigram]Direction{
+    // 乾南坤北，离东坎西，震东北，兑东南，巽西南，艮西北
+    ArrangementFuXi: {
+        TrigramQian: DirectionSouth,
+        TrigramKun:  DirectionNorth,
+        TrigramLi:   DirectionEast,
+        TrigramKan:  DirectionWest,
+        TrigramZhen: DirectionNorthEast,
+        TrigramDui:  DirectionSouthEast,
+        TrigramXun:  DirectionSouthWest,
+        TrigramGen:  DirectionNorthWest,
+    },
+    // 离南坎北，震东兑西，乾西北，坤西南，艮东北，巽东南
+    ArrangementKingWen: {
+        TrigramLi:   DirectionSouth,
+        TrigramKan:  DirectionNorth,
+        TrigramZhen: DirectionEast,
+        TrigramDui:  DirectionWest,
+        TrigramQian: DirectionNorthWest,
+        TrigramKun:  DirectionSouthWest,
+        TrigramGen:  DirectionNorthEast,
+        TrigramXun:  DirectionSouthEast,
+    },
+}
+
+// String 获取排列名称
+func (a Arrangement) String() string {
+    switch a {
+    case ArrangementFuXi:
+        return "先天"
+    case ArrangementKingWen:
+        return "后天"
+    default:
+        return fmt.Sprintf("Arrangement(%d)", uint8(a))
+    }
+}
+
+// Valid 是否为已知排列
+func (a Arrangement) Valid() bool {
+    _, exists := arrangements[a]
+    return exists
+}
+
+// Direction 获取卦在排列中的方位
+func (a Arrangement) Direction(t Trigram) Direction {
+    return arrangements[a][t]
+}
+
+// TrigramAt 获取排列中位于方位 d 的卦
+func (a Arrangement) TrigramAt(d Direction) Trigram {
+    for t, dir := range arrangements[a] {
+        if dir == d {
+            return t
+        }
+    }
+    return TrigramQian
+}
+
+// trigramNames 卦名
+var trigramNames = map[Trigram]string{
+    TrigramQian: "乾",
+    TrigramKun:  "坤",
+    TrigramZhen: "震",
+    TrigramXun:  "巽",
+    TrigramKan:  "坎",
+    TrigramLi:   "离",
+    TrigramGen:  "艮",
+    TrigramDui:  "兑",
+}
+
+// trigramElements 八卦的五行归属
+var trigramElements = map[Trigram]Phase{
+    TrigramQian: PhaseMetal,
+    TrigramDui:  PhaseMetal,
+    TrigramLi:   PhaseFire,
+    TrigramZhen: PhaseWood,
+    TrigramXun:  PhaseWood,
+    TrigramKan:  PhaseWater,
+    TrigramGen:  PhaseEarth,
+    TrigramKun:  PhaseEarth,
+}
+
+// String 获取卦名
+func (t Trigram) String() string {
+    if name, exists := trigramNames[t]; exists {
+        return name
+    }
+    return fmt.Sprintf("Trigram(%d)", uint8(t))
+}
+
+// Element 获取卦的五行归属
+func (t Trigram) Element() Phase {
+    return trigramElements[t]
+}
+
+// Nature 获取卦的阴阳，阳卦多阴、阴卦多阳，乾为纯阳、坤为纯阴
+func (t Trigram) Nature() Nature {
+    switch t {
+    case TrigramQian, TrigramZhen, TrigramKan, TrigramGen:
+        return NatureYang
+    default:
+        return NatureYin
+    }
+}
+
+// YinYangAttribute 卦的阴阳爻比例
+type YinYangAttribute struct {
+    Yin  float64
+    Yang float64
+}
+
+// Attribute 按阴阳爻数计算卦的阴阳比例
+func (t Trigram) Attribute() YinYangAttribute {
+    yang := 0
+    for _, line := range t.Lines() {
+        if line == LineYang {
+            yang++
+        }
+    }
+    return YinYangAttribute{
+        Yin:  float64(3-yang) / 3,
+        Yang: float64(yang) / 3,
+    }
+}
@@ -0,0 +1,335 @@
+// model/calendar.go
+
+package model
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "time"
+)
+
+// ErrInvalidCalendar 干支历为空或柱无效
+var ErrInvalidCalendar = errors.New("无效的干支历")
+
+// SolarTerm 二十四节气，自立春起
+type SolarTerm uint8
+
+const (
+    TermLiChun      SolarTerm = iota // 立春
+    TermYuShui                       // 雨水
+    TermJingZhe                      // 惊蛰
+    TermChunFen                      // 春分
+    TermQingMing                     // 清明
+    TermGuYu                         // 谷雨
+    TermLiXia                        // 立夏
+    TermXiaoMan                      // 小满
+    TermMangZhong                    // 芒种
+    TermXiaZhi                       // 夏至
+    TermXiaoShu                      // 小暑
+    TermDaShu                        // 大暑
+    TermLiQiu                        // 立秋
+    TermChuShu                       // 处暑
+    TermBaiLu                        // 白露
+    TermQiuFen                       // 秋分
+    TermHanLu                        // 寒露
+    TermShuangJiang                  // 霜降
+    TermLiDong                       // 立冬
+    TermXiaoXue                      // 小雪
+    TermDaXue                        // 大雪
+    TermDongZhi                      // 冬至
+    TermXiaoHan                      // 小寒
+    TermDaHan                        // 大寒
+)
+
+// solarTermCount 节气数量
+const solarTermCount = 24
+
+var solarTermNames = [solarTermCount]string{
+    "立春", "雨水", "惊蛰", "春分", "清明", "谷雨",
+    "立夏", "小满", "芒种", "夏至", "小暑", "大暑",
+    "立秋", "处暑", "白露", "秋分", "寒露", "霜降",
+    "立冬", "小雪", "大雪", "冬至", "小寒", "大寒",
+}
+
+// 太阳运动常数
+const (
+    liChunLongitude = 315.0     // 立春的太阳视黄经
+    tropicalYear    = 365.2422  // 回归年的天数
+    unixEpochJD     = 2440587.5 // 1970-01-01T00:00Z 的儒略日
+    j2000JD         = 2451545.0 // J2000.0 的儒略日
+)
+
+// String 获取节气名称
+func (s SolarTerm) String() string {
+    if s < solarTermCount {
+        return solarTermNames[s]
+    }
+    return fmt.Sprintf("SolarTerm(%d)", uint8(s))
+}
+
+// Longitude 节气对应的太阳视黄经，单位度
+func (s SolarTerm) Longitude() float64 {
+    return math.Mod(liChunLongitude+15*float64(s), 360)
+}
+
+// IsJie 是否为节（立春、惊蛰等月首），否则为中气
+func (s SolarTerm) IsJie() bool {
+    return s%2 == 0
+}
+
+// julianDay 获取 t 的儒略日
+func julianDay(t time.Time) float64 {
+    return unixEpochJD + float64(t.UnixNano())/float64(24*time.Hour)
+}
+
+// normalizeDegrees 将角度规范到 [0, 360)
+func normalizeDegrees(deg float64) float64 {
+    deg = math.Mod(deg, 360)
+    if deg < 0 {
+        deg += 360
+    }
+    return deg
+}
+
+// solveLongitude 自 guess 起迭代求太阳视黄经为 target 的时刻
+func solveLongitude(target float64, guess time.Time) time.Time {
+    t := guess
+    for i := 0; i < 10; i++ {
+        diff := normalizeDegrees(target-SolarLongitude(t)+180) - 180
+        step := time.Duration(diff / 360 * tropicalYear * float64(24*time.Hour))
+        t = t.Add(step)
+        if step.Abs() < time.Second {
+            break
+        }
+    }
+
+    // 取太阳视黄经到达 target 的第一秒，使边界时刻与按黄经判定的结果一致
+    t = t.Truncate(time.Second)
+    for reached(t, target) {
+        t = t.Add(-time.Second)
+    }
+    for !reached(t, target) {
+        t = t.Add(time.Second)
+    }
+    return t
+}
+
+// reached t 时刻太阳视黄经是否已到达 target
+func reached(t time.Time, target float64) bool {
+    return normalizeDegrees(SolarLongitude(t)-target+180) >= 180
+}
+
+// SolarTermTime 获取公历 year 年中节气 term 的时刻，每个节气每年恰有一次
+func SolarTermTime(year int, term SolarTerm) time.Time {
+    // 元旦前后太阳视黄经约为 280 度
+    start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+    days := normalizeDegrees(term.Longitude()-280) / 360 * tropicalYear
+    return solveLongitude(term.Longitude(), start.Add(time.Duration(days*float64(24*time.Hour))))
+}
+
+// SolarTermAt 获取 t 所处的节气及其开始时刻
+func SolarTermAt(t time.Time) (SolarTerm, time.Time) {
+    offset := normalizeDegrees(SolarLongitude(t) - liChunLongitude)
+    term := SolarTerm(offset / 15)
+    since := (offset - 15*float64(term)) / 360 * tropicalYear
+    return term, solveLongitude(term.Longitude(), t.Add(-time.Duration(since*float64(24*time.Hour))))
+}
+
+// Pillar 干支纪时的粒度
+type Pillar uint8
+
+const (
+    PillarYear  Pillar = iota // 年柱，以立春为界
+    PillarMonth               // 月柱，以节为界
+    PillarDay                 // 日柱
+    PillarHour                // 时柱，每个时辰两小时
+)
+
+// Valid 是否为有效的柱
+func (p Pillar) Valid() bool {
+    return p <= PillarHour
+}
+
+// String 获取柱名
+func (p Pillar) String() string {
+    switch p {
+    case PillarYear:
+        return "年柱"
+    case PillarMonth:
+        return "月柱"
+    case PillarDay:
+        return "日柱"
+    case PillarHour:
+        return "时柱"
+    default:
+        return fmt.Sprintf("Pillar(%d)", uint8(p))
+    }
+}
+
+// FourPillars 四柱
+type FourPillars struct {
+    Year  GanZhiPair
+    Month GanZhiPair
+    Day   GanZhiPair
+    Hour  GanZhiPair
+}
+
+// String 以年月日时的顺序输出四柱
+func (fp FourPillars) String() string {
+    return fmt.Sprintf("%s年 %s月 %s日 %s时", fp.Year, fp.Month, fp.Day, fp.Hour)
+}
+
+// CalendarConfig 干支历配置
+type CalendarConfig struct {
+    Location      *time.Location // 日柱与时柱所用的当地时间，为 nil 时使用东八区
+    ZiHourNextDay bool           // 23 时起的子时是否已算作次日
+}
+
+// DefaultCalendarConfig 默认配置：东八区，23 时换日
+func DefaultCalendarConfig() CalendarConfig {
+    return CalendarConfig{
+        Location:      chinaStandardTime,
+        ZiHourNextDay: true,
+    }
+}
+
+// chinaStandardTime 东八区
+var chinaStandardTime = time.FixedZone("CST", 8*60*60)
+
+// Calendar 干支历
+// 年柱与月柱由太阳视黄经确定，与时区无关；日柱与时柱按配置时区的当地时间计算
+type Calendar struct {
+    location      *time.Location
+    ziHourNextDay bool
+}
+
+// NewCalendar 创建干支历
+func NewCalendar(config CalendarConfig) *Calendar {
+    if config.Location == nil {
+        config.Location = chinaStandardTime
+    }
+    return &Calendar{
+        location:      config.Location,
+        ziHourNextDay: config.ZiHourNextDay,
+    }
+}
+
+// DefaultCalendar 以默认配置创建干支历
+func DefaultCalendar() *Calendar {
+    return NewCalendar(DefaultCalendarConfig())
+}
+
+// Location 获取日柱与时柱所用的时区
+func (c *Calendar) Location() *time.Location {
+    return c.location
+}
+
+// FourPillars 获取 t 的四柱
+func (c *Calendar) FourPillars(t time.Time) FourPillars {
+    year := c.Year(t)
+    return FourPillars{
+        Year:  year,
+        Month: c.month(t, year),
+        Day:   c.Day(t),
+        Hour:  c.Hour(t),
+    }
+}
+
+// Pillar 获取 t 在指定粒度上的干支
+func (c *Calendar) Pillar(t time.Time, p Pillar) GanZhiPair {
+    switch p {
+    case PillarYear:
+        return c.Year(t)
+    case PillarMonth:
+        return c.Month(t)
+    case PillarDay:
+        return c.Day(t)
+    default:
+        return c.Hour(t)
+    }
+}
+
+// Year 获取年柱，立春前仍属上一年
+func (c *Calendar) Year(t time.Time) GanZhiPair {
+    // 上半年太阳尚未到达立春的黄经时仍属上一年
+    year := t.UTC().Year()
+    if t.UTC().Month() < time.June && normalizeDegrees(SolarLongitude(t)-liChunLongitude) >= 180 {
+        year--
+    }
+    // 公元 4 年为甲子年
+    return GanZhiOf(year - 4)
+}
+
+// Month 获取月柱，立春所在的月为寅月
+func (c *Calendar) Month(t time.Time) GanZhiPair {
+    return c.month(t, c.Year(t))
+}
+
+// month 按年干起月：甲己之年丙作首，乙庚之岁戊为头，丙辛之年寻庚上，丁壬壬寅顺水流，戊癸甲寅为岁首
+func (c *Calendar) month(t time.Time, year GanZhiPair) GanZhiPair {
+    index := int(normalizeDegrees(SolarLongitude(t)-liChunLongitude) / 30)
+    // 自寅月起地支顺排，天干由首月天干顺推
+    first := (int(year.Gan())%5*2 + 2) % 10
+    pair, _ := NewGanZhiPair(Gan((first+index)%10), Zhi((int(ZhiYin)+index)%12))
+    return pair
+}
+
+// dayNumber 获取 t 当地日期的儒略日数
+func (c *Calendar) dayNumber(t time.Time) int {
+    local := t.In(c.location)
+    midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+    return int(math.Floor(float64(midnight.Unix())/86400)) + int(unixEpochJD+0.5)
+}
+
+// Day 获取日柱
+func (c *Calendar) Day(t time.Time) GanZhiPair {
+    day := c.dayNumber(t)
+    if c.ziHourNextDay && t.In(c.location).Hour() >= 23 {
+        day++
+    }
+    // 儒略日数加 49 后对 60 取余即为日干支序号
+    return GanZhiOf(day + 49)
+}
+
+// Hour 获取时柱，时辰自 23 时起每两小时一个
+// 时干支连续循环，子时的天干总是由其所开启的那一天按日干起时
+func (c *Calendar) Hour(t time.Time) GanZhiPair {
+    hour := t.In(c.location).Hour()
+    return GanZhiOf((c.dayNumber(t)+49)*12 + (hour+1)/2)
+}
+
+// Next 获取 t 之后指定粒度上的干支首次变化的时刻
+func (c *Calendar) Next(t time.Time, p Pillar) time.Time {
+    switch p {
+    case PillarYear:
+        next := SolarTermTime(t.UTC().Year(), TermLiChun)
+        if !next.After(t) {
+            next = SolarTermTime(t.UTC().Year()+1, TermLiChun)
+        }
+        return next
+    case PillarMonth:
+        offset := normalizeDegrees(SolarLongitude(t) - liChunLongitude)
+        index := int(offset / 30)
+        target := normalizeDegrees(liChunLongitude + 30*float64(index+1))
+        days := (30*float64(index+1) - offset) / 360 * tropicalYear
+        return solveLongitude(target, t.Add(time.Duration(days*float64(24*time.Hour))))
+    case PillarDay:
+        local := t.In(c.location)
+        boundary := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, c.location)
+        if c.ziHourNextDay {
+            boundary = boundary.Add(-time.Hour)
+        }
+        for !boundary.After(t) {
+            boundary = boundary.AddDate(0, 0, 1)
+        }
+        return boundary
+    default:
+        local := t.In(c.location)
+        boundary := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), 0, 0, 0, c.location)
+        if local.Hour()%2 == 1 {
+            return boundary.Add(2 * time.Hour)
+        }
+        return boundary.Add(time.Hour)
+    }
+}
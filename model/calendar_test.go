@@ -0,0 +1,99 @@
+// model/calendar_test.go
+
+package model
+
+import (
+    "testing"
+    "time"
+)
+
+// 紫金山天文台公布的节气时刻，东八区，精确到分钟
+var publishedTerms = []struct {
+    year int
+    term SolarTerm
+    at   string
+}{
+    {1984, TermLiChun, "1984-02-04 23:19"},
+    {2000, TermLiChun, "2000-02-04 20:40"},
+    {2000, TermDongZhi, "2000-12-21 21:37"},
+    {2024, TermLiChun, "2024-02-04 16:27"},
+    {2024, TermChunFen, "2024-03-20 11:06"},
+    {2024, TermXiaZhi, "2024-06-21 04:51"},
+    {2024, TermQiuFen, "2024-09-22 20:44"},
+    {2024, TermDongZhi, "2024-12-21 17:21"},
+}
+
+func TestSolarTermTimeMatchesPublished(t *testing.T) {
+    for _, c := range publishedTerms {
+        want, err := time.ParseInLocation("2006-01-02 15:04", c.at, chinaStandardTime)
+        if err != nil {
+            t.Fatal(err)
+        }
+        got := SolarTermTime(c.year, c.term)
+        if diff := got.Sub(want); diff < -time.Minute || diff > time.Minute {
+            t.Errorf("%d %s = %s, want %s ±1m", c.year, c.term, got.In(chinaStandardTime).Format(time.DateTime), c.at)
+        }
+    }
+}
+
+func TestSolarTermAtReturnsTermStart(t *testing.T) {
+    start := SolarTermTime(2024, TermLiChun)
+    term, since := SolarTermAt(start.Add(time.Hour))
+    if term != TermLiChun || !since.Equal(start) {
+        t.Fatalf("SolarTermAt = %s %s, want %s %s", term, since, TermLiChun, start)
+    }
+}
+
+func TestFourPillars(t *testing.T) {
+    cal := DefaultCalendar()
+    cases := []struct {
+        at   string
+        want string
+    }{
+        {"2000-01-01 12:00", "己卯年 丙子月 戊午日 戊午时"},
+        {"1984-02-04 23:30", "甲子年 丙寅月 己巳日 甲子时"},
+        {"1949-10-01 15:00", "己丑年 癸酉月 甲子日 壬申时"},
+    }
+    for _, c := range cases {
+        at, err := time.ParseInLocation("2006-01-02 15:04", c.at, chinaStandardTime)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if got := cal.FourPillars(at).String(); got != c.want {
+            t.Errorf("FourPillars(%s) = %s, want %s", c.at, got, c.want)
+        }
+    }
+}
+
+func TestYearAndMonthChangeAtLiChun(t *testing.T) {
+    cal := DefaultCalendar()
+    liChun := SolarTermTime(1984, TermLiChun)
+
+    before, after := liChun.Add(-time.Minute), liChun.Add(time.Minute)
+    if got := cal.Year(before).String(); got != "癸亥" {
+        t.Errorf("year before 立春 = %s, want 癸亥", got)
+    }
+    if got := cal.Month(before).String(); got != "乙丑" {
+        t.Errorf("month before 立春 = %s, want 乙丑", got)
+    }
+    if got := cal.Year(after).String(); got != "甲子" {
+        t.Errorf("year after 立春 = %s, want 甲子", got)
+    }
+    if got := cal.Month(after).String(); got != "丙寅" {
+        t.Errorf("month after 立春 = %s, want 丙寅", got)
+    }
+    if next := cal.Next(before, PillarYear); !next.Equal(liChun) {
+        t.Errorf("Next(PillarYear) = %s, want %s", next, liChun)
+    }
+}
+
+func TestZiHourStartsNextDay(t *testing.T) {
+    at := time.Date(2000, time.January, 1, 23, 30, 0, 0, chinaStandardTime)
+    if got := DefaultCalendar().Day(at).String(); got != "己未" {
+        t.Errorf("day at 23:30 = %s, want 己未", got)
+    }
+    late := NewCalendar(CalendarConfig{Location: chinaStandardTime})
+    if got := late.Day(at).String(); got != "戊午" {
+        t.Errorf("day at 23:30 without zi-hour rollover = %s, want 戊午", got)
+    }
+}
@@ -0,0 +1,25 @@
+// model/clock.go
+
+package model
+
+import (
+    "github.com/Corphon/daoframe/core"
+    "github.com/Corphon/daoframe/core/clock"
+)
+
+// clockOf 获取上下文携带的时钟，未设置时使用真实时钟
+// 通过 clock.NewContext 构造上下文即可让模型在虚拟时间中运行
+func clockOf(ctx *core.DaoContext) clock.Clock {
+    if ctx == nil {
+        return clock.Real()
+    }
+    return clock.FromContext(ctx)
+}
+
+// scheduleOf 获取上下文携带的昼夜时刻表，未设置时使用默认时刻表
+func scheduleOf(ctx *core.DaoContext) clock.Schedule {
+    if ctx == nil {
+        return clock.DefaultSchedule()
+    }
+    return clock.ScheduleFromContext(ctx)
+}
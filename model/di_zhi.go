@@ -48,6 +48,8 @@ type DiZhi struct {
     mu       sync.RWMutex
     branches map[Zhi]*Branch
     current  Zhi
+    calendar *Calendar // 当前地支由干支历推算
+    pillar   Pillar    // 取干支历中的哪一柱
     
     // 关联系统
     tianGan  *TianGan
@@ -66,6 +68,7 @@ type DiZhi struct {
     state      *state.StateManager
     ctx        *core.DaoContext
     metrics    *Metrics
+    reset      chan struct{} // 干支历变化时唤醒 runCycle 重新计算换柱时刻
     done       chan struct{}
 }
 
@@ -84,7 +87,9 @@ func NewDiZhi(ctx *core.DaoContext, tg *TianGan, wx *WuXing) *DiZhi {
         tianGan:   tg,
         wuXing:    wx,
         ctx:       ctx,
-        cycleTime: time.Hour * 2,
+        calendar:  DefaultCalendar(),
+        pillar:    PillarHour,
+        reset:     make(chan struct{}, 1),
         done:      make(chan struct{}),
     }
     
@@ -125,7 +130,25 @@ func (dz *DiZhi) initBranches() {
         }
     }
     
-    dz.current = ZhiZi // 默认从子开始
+    dz.current = dz.calendar.Pillar(clockOf(dz.ctx).Now(), dz.pillar).Zhi()
+}
+
+// SetCalendar 设置推算当前地支的干支历与柱，默认取东八区的时柱
+func (dz *DiZhi) SetCalendar(calendar *Calendar, pillar Pillar) error {
+    if calendar == nil || !pillar.Valid() {
+        return ErrInvalidCalendar
+    }
+    dz.mu.Lock()
+    dz.calendar = calendar
+    dz.pillar = pillar
+    dz.mu.Unlock()
+
+    dz.cycle()
+    select {
+    case dz.reset <- struct{}{}:
+    default:
+    }
+    return nil
 }
 
 // Start 启动地支系统
@@ -136,36 +159,49 @@ func (dz *DiZhi) Start() error {
         return errors.New("地支系统已在运行")
     }
     dz.running = true
-    dz.lastCycle = time.Now()
+    dz.lastCycle = clockOf(dz.ctx).Now()
     dz.mu.Unlock()
     
     go dz.runCycle()
     return nil
 }
 
-// runCycle 运行地支周期
+// runCycle 运行地支周期，在干支历的每个换柱时刻轮转
 func (dz *DiZhi) runCycle() {
-    ticker := time.NewTicker(dz.cycleTime)
-    defer ticker.Stop()
-    
+    c := clockOf(dz.ctx)
     for {
+        dz.mu.RLock()
+        next := dz.calendar.Next(c.Now(), dz.pillar)
+        dz.mu.RUnlock()
+
+        timer := c.NewTimer(next.Sub(c.Now()))
         select {
         case <-dz.done:
+            timer.Stop()
             return
-        case <-ticker.C:
+        case <-timer.C():
             dz.cycle()
+        case <-dz.reset:
+            timer.Stop()
         }
     }
 }
 
-// cycle 执行一次地支循环
+// cycle 地支轮转至干支历推算的当前地支
 func (dz *DiZhi) cycle() {
+    now := clockOf(dz.ctx).Now()
+
     dz.mu.Lock()
     defer dz.mu.Unlock()
     
+    zhi := dz.calendar.Pillar(now, dz.pillar).Zhi()
+    if zhi == dz.current {
+        return
+    }
+
     // 能量转换
     current := dz.branches[dz.current]
-    next := dz.branches[(dz.current+1)%12]
+    next := dz.branches[zhi]
     
     // 计算能量传递
     transfer := current.Energy / 10
@@ -186,8 +222,8 @@ func (dz *DiZhi) cycle() {
     }
     
     // 更新当前地支
-    dz.current = (dz.current + 1) % 12
-    dz.lastCycle = time.Now()
+    dz.current = zhi
+    dz.lastCycle = now
 }
 
 // GetCurrent 获取当前地支信息
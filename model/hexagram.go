@@ -0,0 +1,356 @@
+// model/hexagram.go
+
+package model
+
+import (
+    "errors"
+    "fmt"
+    "sync"
+    "time"
+
+    "github.com/Corphon/daoframe/core"
+)
+
+// ErrInvalidLineValue 无效的爻值
+var ErrInvalidLineValue = errors.New("无效的爻值")
+
+// Hexagram 六十四卦，第 i 位为自下而上第 i 爻，1 为阳爻
+type Hexagram uint8
+
+// hexagramCount 卦的数量
+const hexagramCount = 64
+
+// kingWenNumbers 文王卦序，下标为 [上卦][下卦]
+var kingWenNumbers = [trigramCount][trigramCount]uint8{
+    //           乾  坤  震  巽  坎  离  艮  兑
+    TrigramQian: {1, 12, 25, 44, 6, 13, 33, 10},
+    TrigramKun:  {11, 2, 24, 46, 7, 36, 15, 19},
+    TrigramZhen: {34, 16, 51, 32, 40, 55, 62, 54},
+    TrigramXun:  {9, 20, 42, 57, 59, 37, 53, 61},
+    TrigramKan:  {5, 8, 3, 48, 29, 63, 39, 60},
+    TrigramLi:   {14, 35, 21, 50, 64, 30, 56, 38},
+    TrigramGen:  {26, 23, 27, 18, 4, 22, 52, 41},
+    TrigramDui:  {43, 45, 17, 28, 47, 49, 31, 58},
+}
+
+// hexagramNames 卦名，按文王卦序
+var hexagramNames = [hexagramCount + 1]string{
+    "",
+    "乾", "坤", "屯", "蒙", "需", "讼", "师", "比",
+    "小畜", "履", "泰", "否", "同人", "大有", "谦", "豫",
+    "随", "蛊", "临", "观", "噬嗑", "贲", "剥", "复",
+    "无妄", "大畜", "颐", "大过", "坎", "离", "咸", "恒",
+    "遁", "大壮", "晋", "明夷", "家人", "睽", "蹇", "解",
+    "损", "益", "夬", "姤", "萃", "升", "困", "井",
+    "革", "鼎", "震", "艮", "渐", "归妹", "丰", "旅",
+    "巽", "兑", "涣", "节", "中孚", "小过", "既济", "未济",
+}
+
+// trigramBits 三爻按自下而上编码为低三位
+func trigramBits(t Trigram) Hexagram {
+    var bits Hexagram
+    for i, line := range t.Lines() {
+        if line == LineYang {
+            bits |= 1 << i
+        }
+    }
+    return bits
+}
+
+// NewHexagram 由上卦与下卦组成六十四卦
+func NewHexagram(upper, lower Trigram) Hexagram {
+    return trigramBits(upper)<<3 | trigramBits(lower)
+}
+
+// HexagramOf 由自下而上的六爻获取卦
+func HexagramOf(lines [6]Line) Hexagram {
+    var h Hexagram
+    for i, line := range lines {
+        if line == LineYang {
+            h |= 1 << i
+        }
+    }
+    return h
+}
+
+// HexagramByNumber 按文王卦序获取卦，序号为 1-64
+func HexagramByNumber(n int) (Hexagram, bool) {
+    for upper := Trigram(0); upper < trigramCount; upper++ {
+        for lower := Trigram(0); lower < trigramCount; lower++ {
+            if int(kingWenNumbers[upper][lower]) == n {
+                return NewHexagram(upper, lower), true
+            }
+        }
+    }
+    return 0, false
+}
+
+// Line 获取自下而上第 i 爻，i 为 0-5
+func (h Hexagram) Line(i int) Line {
+    return Line(h >> i & 1)
+}
+
+// Lines 获取卦的六爻，自下而上
+func (h Hexagram) Lines() [6]Line {
+    var lines [6]Line
+    for i := range lines {
+        lines[i] = h.Line(i)
+    }
+    return lines
+}
+
+// Lower 获取下卦（内卦）
+func (h Hexagram) Lower() Trigram {
+    return TrigramOf([3]Line{h.Line(0), h.Line(1), h.Line(2)})
+}
+
+// Upper 获取上卦（外卦）
+func (h Hexagram) Upper() Trigram {
+    return TrigramOf([3]Line{h.Line(3), h.Line(4), h.Line(5)})
+}
+
+// Number 获取文王卦序 1-64
+func (h Hexagram) Number() int {
+    return int(kingWenNumbers[h.Upper()][h.Lower()])
+}
+
+// String 获取卦名
+func (h Hexagram) String() string {
+    if h >= hexagramCount {
+        return fmt.Sprintf("Hexagram(%d)", uint8(h))
+    }
+    return hexagramNames[h.Number()]
+}
+
+// Change 变动指定的爻，得到之卦
+func (h Hexagram) Change(changing ChangingLines) Hexagram {
+    return (h ^ Hexagram(changing)) & (hexagramCount - 1)
+}
+
+// Nuclear 获取互卦：以二至四爻为下卦，三至五爻为上卦
+func (h Hexagram) Nuclear() Hexagram {
+    lower := TrigramOf([3]Line{h.Line(1), h.Line(2), h.Line(3)})
+    upper := TrigramOf([3]Line{h.Line(2), h.Line(3), h.Line(4)})
+    return NewHexagram(upper, lower)
+}
+
+// ChangingLines 变爻，第 i 位为自下而上第 i 爻
+type ChangingLines uint8
+
+// Has 第 i 爻是否为变爻
+func (c ChangingLines) Has(i int) bool {
+    return c>>i&1 == 1
+}
+
+// Count 变爻数量
+func (c ChangingLines) Count() int {
+    n := 0
+    for i := 0; i < 6; i++ {
+        if c.Has(i) {
+            n++
+        }
+    }
+    return n
+}
+
+// Positions 变爻位置，自下而上，从 0 起
+func (c ChangingLines) Positions() []int {
+    var positions []int
+    for i := 0; i < 6; i++ {
+        if c.Has(i) {
+            positions = append(positions, i)
+        }
+    }
+    return positions
+}
+
+// LineValue 筮得的爻值
+type LineValue uint8
+
+const (
+    LineOldYin    LineValue = 6 // 老阴，变爻
+    LineYoungYang LineValue = 7 // 少阳
+    LineYoungYin  LineValue = 8 // 少阴
+    LineOldYang   LineValue = 9 // 老阳，变爻
+)
+
+// Line 获取爻的阴阳
+func (v LineValue) Line() Line {
+    if v == LineYoungYang || v == LineOldYang {
+        return LineYang
+    }
+    return LineYin
+}
+
+// Changing 是否为变爻
+func (v LineValue) Changing() bool {
+    return v == LineOldYin || v == LineOldYang
+}
+
+// Valid 是否为有效爻值
+func (v LineValue) Valid() bool {
+    return v >= LineOldYin && v <= LineOldYang
+}
+
+// Reading 一次筮得的本卦与变爻
+type Reading struct {
+    Primary  Hexagram
+    Changing ChangingLines
+}
+
+// Cast 由自下而上的六个爻值得到本卦与变爻
+func Cast(values [6]LineValue) (Reading, error) {
+    var reading Reading
+    for i, v := range values {
+        if !v.Valid() {
+            return Reading{}, fmt.Errorf("%w: 第 %d 爻为 %d", ErrInvalidLineValue, i+1, v)
+        }
+        if v.Line() == LineYang {
+            reading.Primary |= 1 << i
+        }
+        if v.Changing() {
+            reading.Changing |= 1 << i
+        }
+    }
+    return reading, nil
+}
+
+// Derived 获取之卦
+func (r Reading) Derived() Hexagram {
+    return r.Primary.Change(r.Changing)
+}
+
+// Nuclear 获取本卦的互卦
+func (r Reading) Nuclear() Hexagram {
+    return r.Primary.Nuclear()
+}
+
+// HexagramTransition 卦变事件
+type HexagramTransition struct {
+    From      Hexagram
+    To        Hexagram
+    Changing  ChangingLines
+    Timestamp time.Time
+}
+
+// HexagramObserver 卦变观察者
+type HexagramObserver interface {
+    OnHexagramTransition(transition HexagramTransition)
+}
+
+// HexagramObserverFunc 以函数实现的卦变观察者
+type HexagramObserverFunc func(transition HexagramTransition)
+
+// OnHexagramTransition 调用函数
+func (f HexagramObserverFunc) OnHexagramTransition(transition HexagramTransition) {
+    f(transition)
+}
+
+// hexagramSubscription 卦变订阅
+type hexagramSubscription struct {
+    id       uint64
+    observer HexagramObserver
+}
+
+// HexagramState 当前卦象，卦变时通知观察者
+type HexagramState struct {
+    mu        sync.RWMutex
+    ctx       *core.DaoContext
+    current   Hexagram
+    observers []hexagramSubscription
+    nextID    uint64
+}
+
+// NewHexagramState 创建卦象状态
+func NewHexagramState(ctx *core.DaoContext, initial Hexagram) *HexagramState {
+    return &HexagramState{
+        ctx:     ctx,
+        current: initial & (hexagramCount - 1),
+    }
+}
+
+// Current 获取当前卦
+func (hs *HexagramState) Current() Hexagram {
+    hs.mu.RLock()
+    defer hs.mu.RUnlock()
+    return hs.current
+}
+
+// Change 变动当前卦的指定爻，无变爻时不产生卦变
+func (hs *HexagramState) Change(changing ChangingLines) HexagramTransition {
+    hs.mu.Lock()
+    transition := HexagramTransition{
+        From:      hs.current,
+        To:        hs.current.Change(changing),
+        Changing:  changing & (hexagramCount - 1),
+        Timestamp: clockOf(hs.ctx).Now(),
+    }
+    hs.current = transition.To
+    hs.mu.Unlock()
+
+    if transition.Changing != 0 {
+        hs.notify(transition)
+    }
+    return transition
+}
+
+// Apply 以筮得的结果替换当前卦：先变为本卦，再按变爻变为之卦
+func (hs *HexagramState) Apply(reading Reading) []HexagramTransition {
+    hs.mu.Lock()
+    now := clockOf(hs.ctx).Now()
+    var transitions []HexagramTransition
+    if hs.current != reading.Primary {
+        transitions = append(transitions, HexagramTransition{
+            From:      hs.current,
+            To:        reading.Primary,
+            Changing:  ChangingLines(hs.current ^ reading.Primary),
+            Timestamp: now,
+        })
+    }
+    if reading.Changing != 0 {
+        transitions = append(transitions, HexagramTransition{
+            From:      reading.Primary,
+            To:        reading.Derived(),
+            Changing:  reading.Changing,
+            Timestamp: now,
+        })
+    }
+    hs.current = reading.Derived()
+    hs.mu.Unlock()
+
+    for _, transition := range transitions {
+        hs.notify(transition)
+    }
+    return transitions
+}
+
+// Subscribe 订阅卦变，返回取消订阅的函数
+func (hs *HexagramState) Subscribe(observer HexagramObserver) func() {
+    hs.mu.Lock()
+    hs.nextID++
+    sub := hexagramSubscription{id: hs.nextID, observer: observer}
+    hs.observers = append(hs.observers, sub)
+    hs.mu.Unlock()
+
+    return func() {
+        hs.mu.Lock()
+        defer hs.mu.Unlock()
+        for i, s := range hs.observers {
+            if s.id == sub.id {
+                hs.observers = append(hs.observers[:i], hs.observers[i+1:]...)
+                return
+            }
+        }
+    }
+}
+
+// notify 在锁外通知观察者
+func (hs *HexagramState) notify(transition HexagramTransition) {
+    hs.mu.RLock()
+    observers := append([]hexagramSubscription(nil), hs.observers...)
+    hs.mu.RUnlock()
+
+    for _, sub := range observers {
+        sub.observer.OnHexagramTransition(transition)
+    }
+}
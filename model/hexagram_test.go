@@ -0,0 +1,89 @@
+// model/hexagram_test.go
+
+package model
+
+import "testing"
+
+func TestKingWenNumbersCoverAllHexagrams(t *testing.T) {
+    seen := make(map[int]Hexagram, hexagramCount)
+    for h := Hexagram(0); h < hexagramCount; h++ {
+        n := h.Number()
+        if n < 1 || n > hexagramCount {
+            t.Fatalf("%06b has number %d", uint8(h), n)
+        }
+        if prev, dup := seen[n]; dup {
+            t.Fatalf("number %d shared by %06b and %06b", n, uint8(prev), uint8(h))
+        }
+        seen[n] = h
+
+        if got, ok := HexagramByNumber(n); !ok || got != h {
+            t.Fatalf("HexagramByNumber(%d) = %06b, want %06b", n, uint8(got), uint8(h))
+        }
+    }
+    if _, ok := HexagramByNumber(65); ok {
+        t.Fatal("HexagramByNumber(65) succeeded")
+    }
+}
+
+func TestKingWenSequence(t *testing.T) {
+    cases := []struct {
+        upper, lower Trigram
+        number       int
+        name         string
+    }{
+        {TrigramQian, TrigramQian, 1, "乾"},
+        {TrigramKun, TrigramKun, 2, "坤"},
+        {TrigramKan, TrigramZhen, 3, "屯"},
+        {TrigramKun, TrigramQian, 11, "泰"},
+        {TrigramQian, TrigramKun, 12, "否"},
+        {TrigramGen, TrigramKun, 23, "剥"},
+        {TrigramDui, TrigramGen, 31, "咸"},
+        {TrigramZhen, TrigramDui, 54, "归妹"},
+        {TrigramKan, TrigramLi, 63, "既济"},
+        {TrigramLi, TrigramKan, 64, "未济"},
+    }
+    for _, c := range cases {
+        h := NewHexagram(c.upper, c.lower)
+        if h.Number() != c.number || h.String() != c.name {
+            t.Errorf("%s over %s = %d %s, want %d %s", c.upper, c.lower, h.Number(), h, c.number, c.name)
+        }
+        if h.Upper() != c.upper || h.Lower() != c.lower {
+            t.Errorf("%s: upper/lower = %s/%s", c.name, h.Upper(), h.Lower())
+        }
+    }
+}
+
+func TestNuclearHexagram(t *testing.T) {
+    cases := []struct{ from, want int }{
+        {1, 1},   // 乾之互卦为乾
+        {2, 2},   // 坤之互卦为坤
+        {3, 23},  // 屯之互卦为剥
+        {11, 54}, // 泰之互卦为归妹
+        {63, 64}, // 既济之互卦为未济
+        {64, 63}, // 未济之互卦为既济
+    }
+    for _, c := range cases {
+        h, _ := HexagramByNumber(c.from)
+        if got := h.Nuclear().Number(); got != c.want {
+            t.Errorf("Nuclear(%s) = %d, want %d", h, got, c.want)
+        }
+    }
+}
+
+func TestCastChangesOldLines(t *testing.T) {
+    // 初爻老阳，余爻少阳：乾之姤
+    reading, err := Cast([6]LineValue{LineOldYang, LineYoungYang, LineYoungYang, LineYoungYang, LineYoungYang, LineYoungYang})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if reading.Primary.String() != "乾" || reading.Derived().String() != "姤" {
+        t.Fatalf("reading = %s -> %s, want 乾 -> 姤", reading.Primary, reading.Derived())
+    }
+    if got := reading.Changing.Positions(); len(got) != 1 || got[0] != 0 {
+        t.Fatalf("changing = %v, want [0]", got)
+    }
+
+    if _, err := Cast([6]LineValue{5, 7, 7, 7, 7, 7}); err == nil {
+        t.Fatal("Cast accepted line value 5")
+    }
+}
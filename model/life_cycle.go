@@ -8,6 +8,7 @@ import (
     "errors"
     
     "github.com/Corphon/daoframe/core"
+    "github.com/Corphon/daoframe/core/state"
 )
 
 var (
@@ -46,12 +47,14 @@ type LifeEntity struct {
     Birth     time.Time
     LastCycle time.Time
     Duration  time.Duration
+    WuXing    *WuXingState // 实体自身的五行状态，未使用调度器时为 nil
 }
 
 // LifeCycle 生命周期系统
 type LifeCycle struct {
     mu       sync.RWMutex
     entities map[string]*LifeEntity
+    stages   map[string]*state.StateManager // 实体阶段状态机
     observers   []LifeCycleObserver    // 新增：观察者列表
     entityLocks map[string]*sync.RWMutex  // 新增：实体级别锁
     lockShards  []*sync.RWMutex          // 新增：分片锁
@@ -65,6 +68,7 @@ type LifeCycle struct {
     wuXing   *WuXing
     tianGan  *TianGan
     diZhi    *DiZhi
+    wuXingScheduler *WuXingScheduler // 实体五行状态的调度器
     
     // 周期控制
     ctx      *core.DaoContext
@@ -83,8 +87,9 @@ type TransitionHook func(*LifeEntity, LifeStage) error
 
 // NewLifeCycle 创建生命周期系统
 func NewLifeCycle(ctx *core.DaoContext, wx *WuXing, tg *TianGan, dz *DiZhi) *LifeCycle {
-    return &LifeCycle{
+    lc := &LifeCycle{
         entities: make(map[string]*LifeEntity),
+        stages:   make(map[string]*state.StateManager),
         observers:   make([]LifeCycleObserver, 0),
         entityLocks: make(map[string]*sync.RWMutex),
         lockShards:  make([]*sync.RWMutex, 32), // 32个分片锁
@@ -102,6 +107,13 @@ func NewLifeCycle(ctx *core.DaoContext, wx *WuXing, tg *TianGan, dz *DiZhi) *Lif
     return lc
 }
 
+// UseWuXingScheduler 为之后创建的实体分配独立的五行状态，由调度器批量推进
+func (lc *LifeCycle) UseWuXingScheduler(ws *WuXingScheduler) {
+    lc.mu.Lock()
+    defer lc.mu.Unlock()
+    lc.wuXingScheduler = ws
+}
+
 // CreateEntity 创建生命实体
 func (lc *LifeCycle) CreateEntity(id string) (*LifeEntity, error) {
     lc.mu.Lock()
@@ -112,12 +124,13 @@ func (lc *LifeCycle) CreateEntity(id string) (*LifeEntity, error) {
     }
 
     // 创建新实体
+    now := clockOf(lc.ctx).Now()
     entity := &LifeEntity{
         ID:        id,
         Stage:     StageVoid,
         Elements:  make([]LifeElement, 0),
-        Birth:     time.Now(),
-        LastCycle: time.Now(),
+        Birth:     now,
+        LastCycle: now,
         Duration:  0,
     }
 
@@ -143,7 +156,13 @@ func (lc *LifeCycle) CreateEntity(id string) (*LifeEntity, error) {
         })
     }
 
+    if lc.wuXingScheduler != nil {
+        entity.WuXing = NewWuXingState(BalancedStrengths(50))
+        lc.wuXingScheduler.Register(id, entity.WuXing)
+    }
+
     lc.entities[id] = entity
+    lc.stages[id] = state.NewStateManager(lifeCycleMachine)
     return entity, nil
 }
 
@@ -163,14 +182,14 @@ func (lc *LifeCycle) Start() error {
 
 // runCycles 运行生命周期
 func (lc *LifeCycle) runCycles() {
-    ticker := time.NewTicker(time.Hour)
+    ticker := clockOf(lc.ctx).NewTicker(time.Hour)
     defer ticker.Stop()
 
     for {
         select {
         case <-lc.done:
             return
-        case <-ticker.C:
+        case <-ticker.C():
             lc.processCycle()
         }
     }
@@ -181,7 +200,7 @@ func (lc *LifeCycle) processCycle() {
     lc.mu.Lock()
     defer lc.mu.Unlock()
 
-    now := time.Now()
+    now := clockOf(lc.ctx).Now()
     for _, entity := range lc.entities {
         // 更新实体状态
         lc.updateEntityState(entity, now)
@@ -199,22 +218,11 @@ func (lc *LifeCycle) updateEntityState(entity *LifeEntity, now time.Time) {
     entity.Duration = age
     oldStage := entity.Stage
 
-    // 基于年龄和生命力确定阶段
+    // 基于生命力驱动状态机，生命力仍在当前阶段的区间内时没有可用转换
     totalVitality := lc.calculateTotalVitality(entity)
-    
-    switch {
-    case totalVitality == 0:
-        entity.Stage = StageReturn
-    case totalVitality < 20:
-        entity.Stage = StageEnd
-    case totalVitality < 40:
-        entity.Stage = StageDecline
-    case totalVitality < 60:
-        entity.Stage = StageGrowth
-    case totalVitality < 80:
-        entity.Stage = StagePeak
-    default:
-        entity.Stage = StageBirth
+    if sm, exists := lc.stages[entity.ID]; exists {
+        sm.Fire(EventVitality, totalVitality)
+        entity.Stage = stageOf(sm.Current())
     }
     // 如果状态发生变化，通知观察者
     if oldStage != entity.Stage {
@@ -250,10 +258,15 @@ func (lc *LifeCycle) processElementChanges(entity *LifeEntity) {
     for i := range entity.Elements {
         elem := &entity.Elements[i]
         
-        // 基于五行相生相克调整能量
-        if lc.wuXing != nil {
-            strength, _ := lc.wuXing.GetElementStrength(elem.Phase)
-            energyDelta := (int8(strength) - int8(elem.Energy)) / 10
+        // 基于五行相生相克调整能量，优先使用实体自身的五行状态
+        if entity.WuXing != nil || lc.wuXing != nil {
+            var strength float64
+            if entity.WuXing != nil {
+                strength, _ = entity.WuXing.Strength(elem.Phase)
+            } else {
+                strength, _ = lc.wuXing.ElementStrength(elem.Phase)
+            }
+            energyDelta := int8((strength - float64(elem.Energy)) / 10)
             
             newEnergy := int16(elem.Energy) + int16(energyDelta)
             if newEnergy < 0 {
@@ -289,6 +302,7 @@ func (lc *LifeCycle) GetEntity(id string) (*LifeEntity, error) {
         Birth:     entity.Birth,
         LastCycle: entity.LastCycle,
         Duration:  entity.Duration,
+        WuXing:    entity.WuXing,
     }, nil
 }
 
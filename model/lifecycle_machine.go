@@ -0,0 +1,80 @@
+// model/lifecycle_machine.go
+
+package model
+
+import (
+    "github.com/Corphon/daoframe/core/state"
+)
+
+// EventVitality 生命力变化事件，负载为实体总生命力(uint8)
+const EventVitality state.Event = "vitality"
+
+// lifeStageStates 生命阶段与状态机状态的对应
+var lifeStageStates = map[LifeStage]state.State{
+    StageVoid:    state.StateVoid,
+    StageOrigin:  state.StageOrigin,
+    StageBirth:   state.StageBirth,
+    StageGrowth:  state.StageGrowth,
+    StagePeak:    state.StagePeak,
+    StageDecline: state.StageDecline,
+    StageEnd:     state.StageEnd,
+    StageReturn:  state.StageReturn,
+}
+
+// lifeCycleMachine 生命实体共享的状态机
+var lifeCycleMachine = state.MustNewMachine(LifeCycleDefinition())
+
+// vitalityBands 生命力区间 [Min, Max) 对应的生命阶段
+var vitalityBands = []struct {
+    Stage state.State
+    Min   int
+    Max   int
+}{
+    {state.StageReturn, 0, 1},
+    {state.StageEnd, 1, 20},
+    {state.StageDecline, 20, 40},
+    {state.StageGrowth, 40, 60},
+    {state.StagePeak, 60, 80},
+    {state.StageBirth, 80, 256},
+}
+
+// LifeCycleDefinition 生命实体状态机声明
+// 阶段由当前生命力所在区间决定：任一阶段在生命力进入其他区间时直接转向对应阶段，
+// 生命力回升时同样回到先前的阶段
+func LifeCycleDefinition() state.Definition {
+    def := state.LifecycleDefinition()
+    def.Name = "life-entity"
+    def.Transitions = make([]state.Transition, 0, len(def.States)*len(vitalityBands))
+    for _, spec := range def.States {
+        for _, band := range vitalityBands {
+            if band.Stage == spec.Name {
+                continue
+            }
+            def.Transitions = append(def.Transitions, state.Transition{
+                From:  spec.Name,
+                To:    band.Stage,
+                Event: EventVitality,
+                Guard: vitalityWithin(band.Min, band.Max),
+            })
+        }
+    }
+    return def
+}
+
+// vitalityWithin 生命力处于 [min, max) 时放行
+func vitalityWithin(min, max int) state.Guard {
+    return func(tc *state.TransitionContext) bool {
+        vitality, ok := tc.Payload.(uint8)
+        return ok && int(vitality) >= min && int(vitality) < max
+    }
+}
+
+// stageOf 获取状态对应的生命阶段
+func stageOf(s state.State) LifeStage {
+    for stage, st := range lifeStageStates {
+        if st == s {
+            return stage
+        }
+    }
+    return StageVoid
+}
@@ -0,0 +1,247 @@
+// model/solar_position.go
+
+package model
+
+import (
+    "math"
+    "time"
+)
+
+// vsopTerm VSOP87 的周期项：A·cos(B + C·τ)
+type vsopTerm struct {
+    A, B, C float64
+}
+
+// earthLongitude 地球日心黄经的 VSOP87 截断级数（Meeus《天文算法》附录），单位 1e-8 弧度
+var earthLongitude = [][]vsopTerm{
+    { // L0
+        {175347046, 0, 0},
+        {3341656, 4.6692568, 6283.07585},
+        {34894, 4.6261, 12566.1517},
+        {3497, 2.7441, 5753.3849},
+        {3418, 2.8289, 3.5231},
+        {3136, 3.6277, 77713.7715},
+        {2676, 4.4181, 7860.4194},
+        {2343, 6.1352, 3930.2097},
+        {1324, 0.7425, 11506.7698},
+        {1273, 2.0371, 529.691},
+        {1199, 1.1096, 1577.3435},
+        {990, 5.233, 5884.927},
+        {902, 2.045, 26.298},
+        {857, 3.508, 398.149},
+        {780, 1.179, 5223.694},
+        {753, 2.533, 5507.553},
+        {505, 4.583, 18849.228},
+        {492, 4.205, 775.523},
+        {357, 2.92, 0.067},
+        {317, 5.849, 11790.629},
+        {284, 1.899, 796.298},
+        {271, 0.315, 10977.079},
+        {243, 0.345, 5486.778},
+        {206, 4.806, 2544.314},
+        {205, 1.869, 5573.143},
+        {202, 2.458, 6069.777},
+        {156, 0.833, 213.299},
+        {132, 3.411, 2942.463},
+        {126, 1.083, 20.775},
+        {115, 0.645, 0.98},
+        {103, 0.636, 4694.003},
+        {102, 0.976, 15720.839},
+        {102, 4.267, 7.114},
+        {99, 6.21, 2146.17},
+        {98, 0.68, 155.42},
+        {86, 5.98, 161000.69},
+        {85, 1.3, 6275.96},
+        {85, 3.67, 71430.7},
+        {80, 1.81, 17260.15},
+        {79, 3.04, 12036.46},
+        {75, 1.76, 5088.63},
+        {74, 3.5, 3154.69},
+        {74, 4.68, 801.82},
+        {70, 0.83, 9437.76},
+        {62, 3.98, 8827.39},
+        {61, 1.82, 7084.9},
+        {57, 2.78, 6286.6},
+        {56, 4.39, 14143.5},
+        {56, 3.47, 6279.55},
+        {52, 0.19, 12139.55},
+        {52, 1.33, 1748.02},
+        {51, 0.28, 5856.48},
+        {49, 0.49, 1194.45},
+        {41, 5.37, 8429.24},
+        {41, 2.4, 19651.05},
+        {39, 6.17, 10447.39},
+        {37, 6.04, 10213.29},
+        {37, 2.57, 1059.38},
+        {36, 1.71, 2352.87},
+        {36, 1.78, 6812.77},
+        {33, 0.59, 17789.85},
+        {30, 0.44, 83996.85},
+        {30, 2.74, 1349.87},
+        {25, 3.16, 4690.48},
+    },
+    { // L1
+        {628331966747, 0, 0},
+        {206059, 2.678235, 6283.07585},
+        {4303, 2.6351, 12566.1517},
+        {425, 1.59, 3.523},
+        {119, 5.796, 26.298},
+        {109, 2.966, 1577.344},
+        {93, 2.59, 18849.23},
+        {72, 1.14, 529.69},
+        {68, 1.87, 398.15},
+        {67, 4.41, 5507.55},
+        {59, 2.89, 5223.69},
+        {56, 2.17, 155.42},
+        {45, 0.4, 796.3},
+        {36, 0.47, 775.52},
+        {29, 2.65, 7.11},
+        {21, 5.34, 0.98},
+        {19, 1.85, 5486.78},
+        {19, 4.97, 213.3},
+        {17, 2.99, 6275.96},
+        {16, 0.03, 2544.31},
+        {16, 1.43, 2146.17},
+        {15, 1.21, 10977.08},
+        {12, 2.83, 1748.02},
+        {12, 3.26, 5088.63},
+        {12, 5.27, 1194.45},
+        {12, 2.08, 4694},
+        {11, 0.77, 553.57},
+        {10, 1.3, 6286.6},
+        {10, 4.24, 1349.87},
+        {9, 2.7, 242.73},
+        {9, 5.64, 951.72},
+        {8, 5.3, 2352.87},
+        {6, 2.65, 9437.76},
+        {6, 4.67, 4690.48},
+    },
+    { // L2
+        {52919, 0, 0},
+        {8720, 1.0721, 6283.0758},
+        {309, 0.867, 12566.152},
+        {27, 0.05, 3.52},
+        {16, 5.19, 26.3},
+        {16, 3.68, 155.42},
+        {10, 0.76, 18849.23},
+        {9, 2.06, 77713.77},
+        {7, 0.83, 775.52},
+        {5, 4.66, 1577.34},
+        {4, 1.03, 7.11},
+        {4, 3.44, 5573.14},
+        {3, 5.14, 796.3},
+        {3, 6.05, 5507.55},
+        {3, 1.19, 242.73},
+        {3, 6.12, 529.69},
+        {3, 0.31, 398.15},
+        {3, 2.28, 553.57},
+        {2, 4.38, 5223.69},
+        {2, 3.75, 0.98},
+    },
+    { // L3
+        {289, 5.844, 6283.076},
+        {35, 0, 0},
+        {17, 5.49, 12566.15},
+        {3, 5.2, 155.42},
+        {1, 4.72, 3.52},
+        {1, 5.3, 18849.23},
+        {1, 5.97, 242.73},
+    },
+    { // L4
+        {114, 3.142, 0},
+        {8, 4.13, 6283.08},
+        {1, 3.84, 12566.15},
+    },
+    { // L5
+        {1, 3.14, 0},
+    },
+}
+
+// arcsecond 一角秒对应的度数
+const arcsecond = 1.0 / 3600
+
+// SolarLongitude 获取 t 时刻的太阳视黄经，单位度
+// 以 VSOP87 截断级数计算地球日心黄经，加入 FK5 修正、章动与光行差，并按 ΔT 换算力学时，
+// 误差约 1 角秒，对应节气时刻误差在半分钟以内
+func SolarLongitude(t time.Time) float64 {
+    jde := julianDay(t) + deltaT(t)/86400
+    T := (jde - j2000JD) / 36525
+    tau := T / 10
+    rad := math.Pi / 180
+
+    // 地球日心黄经，转为太阳地心几何黄经
+    L, power := 0.0, 1.0
+    for _, series := range earthLongitude {
+        sum := 0.0
+        for _, term := range series {
+            sum += term.A * math.Cos(term.B+term.C*tau)
+        }
+        L += sum * power
+        power *= tau
+    }
+    lambda := L/1e8/rad + 180
+
+    // FK5 修正
+    lambda += -0.09033 * arcsecond
+
+    // 章动主项
+    omega := (125.04452 - 1934.136261*T) * rad
+    sunMean := (280.4665 + 36000.7698*T) * rad
+    moonMean := (218.3165 + 481267.8813*T) * rad
+    nutation := -17.20*math.Sin(omega) - 1.32*math.Sin(2*sunMean) - 0.23*math.Sin(2*moonMean) + 0.21*math.Sin(2*omega)
+    lambda += nutation * arcsecond
+
+    // 光行差，日地距离取自轨道偏心率
+    M := (357.52911 + 35999.05029*T) * rad
+    e := 0.016708634 - 0.000042037*T
+    C := (1.914602-0.004817*T)*math.Sin(M) + (0.019993-0.000101*T)*math.Sin(2*M) + 0.000289*math.Sin(3*M)
+    R := 1.000001018 * (1 - e*e) / (1 + e*math.Cos(M+C*rad))
+    lambda += -20.4898 / R * arcsecond
+
+    return normalizeDegrees(lambda)
+}
+
+// deltaT 获取 t 时刻力学时与世界时之差 ΔT，单位秒
+// 采用 Espenak 与 Meeus 的多项式拟合，1800 年至 2150 年之外使用长期抛物线
+func deltaT(t time.Time) float64 {
+    utc := t.UTC()
+    y := float64(utc.Year()) + (float64(utc.YearDay())-0.5)/365.25
+    longTerm := func() float64 {
+        u := (y - 1820) / 100
+        return -20 + 32*u*u
+    }
+
+    switch {
+    case y < 1800:
+        return longTerm()
+    case y < 1860:
+        x := y - 1800
+        return 13.72 - 0.332447*x + 0.0068612*x*x + 0.0041116*x*x*x - 0.00037436*math.Pow(x, 4) +
+            0.0000121272*math.Pow(x, 5) - 0.0000001699*math.Pow(x, 6) + 0.000000000875*math.Pow(x, 7)
+    case y < 1900:
+        x := y - 1860
+        return 7.62 + 0.5737*x - 0.251754*x*x + 0.01680668*x*x*x - 0.0004473624*math.Pow(x, 4) + math.Pow(x, 5)/233174
+    case y < 1920:
+        x := y - 1900
+        return -2.79 + 1.494119*x - 0.0598939*x*x + 0.0061966*x*x*x - 0.000197*math.Pow(x, 4)
+    case y < 1941:
+        x := y - 1920
+        return 21.20 + 0.84493*x - 0.0761*x*x + 0.0020936*x*x*x
+    case y < 1961:
+        x := y - 1950
+        return 29.07 + 0.407*x - x*x/233 + x*x*x/2547
+    case y < 1986:
+        x := y - 1975
+        return 45.45 + 1.067*x - x*x/260 - x*x*x/718
+    case y < 2005:
+        x := y - 2000
+        return 63.86 + 0.3345*x - 0.060374*x*x + 0.0017275*x*x*x + 0.000651814*math.Pow(x, 4) + 0.00002373599*math.Pow(x, 5)
+    case y < 2050:
+        x := y - 2000
+        return 62.92 + 0.32217*x + 0.005589*x*x
+    case y < 2150:
+        return longTerm() - 0.5628*(2150-y)
+    default:
+        return longTerm()
+    }
+}
@@ -1,4 +1,16 @@
-package basic
+// model/temporal.go
+
+package model
+
+import (
+    "errors"
+    "fmt"
+    "sync"
+    "time"
+)
+
+// ErrInvalidGanZhi 天干与地支阴阳不同，不能配对
+var ErrInvalidGanZhi = errors.New("无效的干支组合")
 
 // TimeSystem 时序系统
 type TimeSystem struct {
@@ -26,6 +38,30 @@ type CyclePattern struct {
     strength   float64
 }
 
+// ganZhiCount 六十甲子
+const ganZhiCount = 60
+
+var (
+    ganNames = [10]string{"甲", "乙", "丙", "丁", "戊", "己", "庚", "辛", "壬", "癸"}
+    zhiNames = [12]string{"子", "丑", "寅", "卯", "辰", "巳", "午", "未", "申", "酉", "戌", "亥"}
+)
+
+// String 获取天干名称
+func (g Gan) String() string {
+    if g <= GanGui {
+        return ganNames[g]
+    }
+    return fmt.Sprintf("Gan(%d)", uint8(g))
+}
+
+// String 获取地支名称
+func (z Zhi) String() string {
+    if z >= ZhiZi && z <= ZhiHai {
+        return zhiNames[z]
+    }
+    return fmt.Sprintf("Zhi(%d)", int(z))
+}
+
 // GanZhiPair 天干地支配对
 type GanZhiPair struct {
     gan        Gan
@@ -33,3 +69,70 @@ type GanZhiPair struct {
     nature     Nature
     element    Phase
 }
+
+// NewGanZhiPair 由天干与地支组成干支，二者须同为阳或同为阴
+func NewGanZhiPair(gan Gan, zhi Zhi) (GanZhiPair, error) {
+    if gan > GanGui || zhi < ZhiZi || zhi > ZhiHai {
+        return GanZhiPair{}, ErrInvalidGanZhi
+    }
+    if int(gan)%2 != int(zhi)%2 {
+        return GanZhiPair{}, fmt.Errorf("%w: %s%s", ErrInvalidGanZhi, gan, zhi)
+    }
+    return GanZhiOf(6*int(gan) - 5*int(zhi)), nil
+}
+
+// GanZhiOf 获取六十甲子中第 index 个干支，甲子为 0，超出范围时循环
+func GanZhiOf(index int) GanZhiPair {
+    index = floorMod(index, ganZhiCount)
+    gan := Gan(index % 10)
+    nature := NatureYang
+    if gan%2 == 1 {
+        nature = NatureYin
+    }
+    return GanZhiPair{
+        gan:     gan,
+        zhi:     Zhi(index % 12),
+        nature:  nature,
+        element: Phase(gan / 2),
+    }
+}
+
+// Gan 获取天干
+func (p GanZhiPair) Gan() Gan {
+    return p.gan
+}
+
+// Zhi 获取地支
+func (p GanZhiPair) Zhi() Zhi {
+    return p.zhi
+}
+
+// Nature 获取阴阳，随天干
+func (p GanZhiPair) Nature() Nature {
+    return p.nature
+}
+
+// Element 获取五行，随天干
+func (p GanZhiPair) Element() Phase {
+    return p.element
+}
+
+// Index 获取在六十甲子中的序号，甲子为 0
+func (p GanZhiPair) Index() int {
+    return floorMod(6*int(p.gan)-5*int(p.zhi), ganZhiCount)
+}
+
+// Next 获取其后第 n 个干支，n 为负时向前
+func (p GanZhiPair) Next(n int) GanZhiPair {
+    return GanZhiOf(p.Index() + n)
+}
+
+// String 获取干支名称，如 甲子
+func (p GanZhiPair) String() string {
+    return p.gan.String() + p.zhi.String()
+}
+
+// floorMod 取非负余数
+func floorMod(a, n int) int {
+    return ((a % n) + n) % n
+}
@@ -0,0 +1,57 @@
+// model/temporal_test.go
+
+package model
+
+import (
+    "errors"
+    "testing"
+)
+
+func TestSexagenaryCycle(t *testing.T) {
+    seen := make(map[string]bool, ganZhiCount)
+    for i := 0; i < ganZhiCount; i++ {
+        p := GanZhiOf(i)
+        if p.Index() != i {
+            t.Fatalf("GanZhiOf(%d).Index() = %d", i, p.Index())
+        }
+        if seen[p.String()] {
+            t.Fatalf("%s appears twice", p)
+        }
+        seen[p.String()] = true
+    }
+
+    cases := []struct {
+        index int
+        want  string
+    }{
+        {0, "甲子"},
+        {1, "乙丑"},
+        {10, "甲戌"},
+        {54, "戊午"},
+        {59, "癸亥"},
+        {60, "甲子"},
+        {-1, "癸亥"},
+    }
+    for _, c := range cases {
+        if got := GanZhiOf(c.index).String(); got != c.want {
+            t.Errorf("GanZhiOf(%d) = %s, want %s", c.index, got, c.want)
+        }
+    }
+    if got := GanZhiOf(59).Next(1).String(); got != "甲子" {
+        t.Errorf("癸亥.Next(1) = %s, want 甲子", got)
+    }
+}
+
+func TestNewGanZhiPair(t *testing.T) {
+    p, err := NewGanZhiPair(GanWu, ZhiWu)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if p.String() != "戊午" || p.Index() != 54 || p.Element() != PhaseEarth || p.Nature() != NatureYang {
+        t.Fatalf("戊午 = %s #%d %v %v", p, p.Index(), p.Element(), p.Nature())
+    }
+
+    if _, err := NewGanZhiPair(GanJia, ZhiChou); !errors.Is(err, ErrInvalidGanZhi) {
+        t.Fatalf("甲丑 err = %v, want ErrInvalidGanZhi", err)
+    }
+}
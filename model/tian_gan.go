@@ -55,6 +55,8 @@ type TianGan struct {
     current   Gan
     wuxing    *WuXing
     ctx       *core.DaoContext
+    calendar  *Calendar // 当前天干由干支历推算
+    pillar    Pillar    // 取干支历中的哪一柱
     // 关联地支系统
     diZhi       *DiZhi
     ganZhiCycle struct {
@@ -68,6 +70,7 @@ type TianGan struct {
     metrics    *Metrics
     observers  []Observer
     changes    chan Gan
+    reset      chan struct{} // 干支历变化时唤醒 run 重新计算换柱时刻
     done       chan struct{}
 }
 
@@ -75,9 +78,12 @@ type TianGan struct {
 func NewTianGan(ctx *core.DaoContext, wx *WuXing) *TianGan {
     tg := &TianGan{
         gans:    make(map[Gan]*GanAttribute),
-        wuxing:  wx,
-        ctx:     ctx,
-        changes: make(chan Gan, 1),
+        wuxing:   wx,
+        ctx:      ctx,
+        calendar: DefaultCalendar(),
+        pillar:   PillarHour,
+        changes:  make(chan Gan, 1),
+        reset:    make(chan struct{}, 1),
         done:    make(chan struct{}),
     }
 
@@ -125,33 +131,62 @@ func (tg *TianGan) initGans() {
         }
     }
 
-    tg.current = GanJia // 从甲开始
+    tg.current = tg.calendar.Pillar(clockOf(tg.ctx).Now(), tg.pillar).Gan()
 }
 
-// run 运行天干循环
-func (tg *TianGan) run() {
-    ticker := time.NewTicker(time.Hour * 2) // 每两小时转动一次
-    defer ticker.Stop()
+// SetCalendar 设置推算当前天干的干支历与柱，默认取东八区的时柱
+func (tg *TianGan) SetCalendar(calendar *Calendar, pillar Pillar) error {
+    if calendar == nil || !pillar.Valid() {
+        return ErrInvalidCalendar
+    }
+    tg.mu.Lock()
+    tg.calendar = calendar
+    tg.pillar = pillar
+    tg.mu.Unlock()
 
+    tg.rotate()
+    select {
+    case tg.reset <- struct{}{}:
+    default:
+    }
+    return nil
+}
+
+// run 运行天干循环，在干支历的每个换柱时刻轮转
+func (tg *TianGan) run() {
+    c := clockOf(tg.ctx)
     for {
+        tg.mu.RLock()
+        next := tg.calendar.Next(c.Now(), tg.pillar)
+        tg.mu.RUnlock()
+
+        timer := c.NewTimer(next.Sub(c.Now()))
         select {
         case <-tg.done:
+            timer.Stop()
             return
-        case <-ticker.C:
+        case <-timer.C():
             tg.rotate()
         case gan := <-tg.changes:
+            timer.Stop()
             tg.handleChange(gan)
+        case <-tg.reset:
+            timer.Stop()
         }
     }
 }
 
-// rotate 天干轮转
+// rotate 天干轮转至干支历推算的当前天干
 func (tg *TianGan) rotate() {
+    now := clockOf(tg.ctx).Now()
+
     tg.mu.Lock()
     defer tg.mu.Unlock()
 
-    // 计算下一个天干
-    next := (tg.current + 1) % 10
+    next := tg.calendar.Pillar(now, tg.pillar).Gan()
+    if next == tg.current {
+        return
+    }
     
     // 更新能量
     prevAttr := tg.gans[tg.current]
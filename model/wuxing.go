@@ -3,6 +3,7 @@
 package model
 
 import (
+    "math"
     "sync"
     "time"
     "errors"
@@ -34,13 +35,40 @@ const (
     RelControl                      // 相克
     RelWeaken                       // 相泄
     RelNeutral                      // 中性
+    RelOveract                      // 相乘：克方过强
+    RelInsult                       // 相侮：被克方反克
 )
 
+// String 获取关系名称
+func (r Relationship) String() string {
+    switch r {
+    case RelGenerate:
+        return "相生"
+    case RelControl:
+        return "相克"
+    case RelWeaken:
+        return "相泄"
+    case RelNeutral:
+        return "中性"
+    case RelOveract:
+        return "相乘"
+    case RelInsult:
+        return "相侮"
+    default:
+        return "未知"
+    }
+}
+
+// PathologyObserver 相乘、相侮的观察者
+type PathologyObserver interface {
+    OnPathology(cycle uint64, transfer Transfer)
+}
+
 // Element 五行元素
 type Element struct {
     mu         sync.RWMutex
     phase      Phase
-    strength   uint8  // 0-100
+    strength   float64 // 0-100
     yinYang    *YinYang
     lastUpdate time.Time
 }
@@ -55,6 +83,10 @@ type WuXing struct {
 
     stateManager *state.StateManager
     relationships map[Phase]map[Phase]Relationship
+    engine       *WuXingEngine
+    lastReport   CycleReport
+    cycleCount   uint64
+    pathologyObservers []PathologyObserver
     cycleControl struct {
         sync.RWMutex
         active bool
@@ -71,6 +103,7 @@ func NewWuXing(ctx *core.DaoContext) *WuXing {
         cycles:   make(chan struct{}, 1),
         done:     make(chan struct{}),
     }
+    wx.engine, _ = NewWuXingEngine(DefaultWuXingEngineConfig())
 
     // 初始化五行元素
     wx.initElements()
@@ -90,21 +123,21 @@ func (wx *WuXing) initElements() {
             phase:      phase,
             strength:   50, // 初始均衡
             yinYang:    NewYinYang(wx.ctx),
-            lastUpdate: time.Now(),
+            lastUpdate: clockOf(wx.ctx).Now(),
         }
     }
 }
 
 // runCycles 运行五行循环
 func (wx *WuXing) runCycles() {
-    ticker := time.NewTicker(time.Hour)
+    ticker := clockOf(wx.ctx).NewTicker(time.Hour)
     defer ticker.Stop()
 
     for {
         select {
         case <-wx.done:
             return
-        case <-ticker.C:
+        case <-ticker.C():
             wx.processCycle()
         case <-wx.cycles:
             wx.processRelationships()
@@ -117,72 +150,86 @@ func (wx *WuXing) processCycle() {
     wx.mu.Lock()
     defer wx.mu.Unlock()
 
-    // 相生循环：木->火->土->金->水->木
-    cycles := []Phase{PhaseWood, PhaseFire, PhaseEarth, PhaseMetal, PhaseWater}
-    
-    for i := 0; i < len(cycles); i++ {
-        current := cycles[i]
-        next := cycles[(i+1)%len(cycles)]
-        
-        // 促进相生关系
-        wx.promote(current, next)
+    s := promoteCycle(wx.strengths())
+    for phase, element := range wx.elements {
+        element.mu.Lock()
+        element.strength = s[phase]
+        element.mu.Unlock()
     }
 }
 
-// promote 促进两个元素间的相生关系
-func (wx *WuXing) promote(from, to Phase) {
-    source := wx.elements[from]
-    target := wx.elements[to]
-
-    source.mu.Lock()
-    target.mu.Lock()
-    defer source.mu.Unlock()
-    defer target.mu.Unlock()
+// promoteCycle 相生循环：木->火->土->金->水->木，依次促进相生关系
+func promoteCycle(s Strengths) Strengths {
+    for p := Phase(0); p < phaseCount; p++ {
+        promote(&s, p, generates(p))
+    }
+    return s
+}
 
+// promote 促进两个元素间的相生关系
+func promote(s *Strengths, from, to Phase) {
     // 根据源元素的强度增强目标元素
-    if source.strength > 20 {
-        energyTransfer := source.strength / 10
-        source.strength -= energyTransfer
-        target.strength += energyTransfer
-        
-        if target.strength > 100 {
-            target.strength = 100
-        }
+    if s[from] > 20 {
+        energyTransfer := math.Min(s[from]/10, 100-s[to])
+        s[from] -= energyTransfer
+        s[to] += energyTransfer
     }
 }
 
 // GetRelationship 获取两个元素间的关系
+// 相克按当前强度判断是否转为相乘；from 为被克方且强度足以反克时为相侮
 func (wx *WuXing) GetRelationship(from, to Phase) Relationship {
-    // 相生关系
-    generates := map[Phase]Phase{
-        PhaseWood:  PhaseFire,
-        PhaseFire:  PhaseEarth,
-        PhaseEarth: PhaseMetal,
-        PhaseMetal: PhaseWater,
-        PhaseWater: PhaseWood,
-    }
+    wx.mu.RLock()
+    defer wx.mu.RUnlock()
+    return wx.relationship(from, to, wx.strengths())
+}
 
-    // 相克关系
-    controls := map[Phase]Phase{
-        PhaseWood:  PhaseEarth,
-        PhaseEarth: PhaseWater,
-        PhaseWater: PhaseFire,
-        PhaseFire:  PhaseMetal,
-        PhaseMetal: PhaseWood,
+// relationship 按给定强度判断关系，调用前须持有锁
+func (wx *WuXing) relationship(from, to Phase, s Strengths) Relationship {
+    if from >= phaseCount || to >= phaseCount || from == to {
+        return RelNeutral
     }
 
-    if generates[from] == to {
+    switch {
+    case generates(from) == to:
         return RelGenerate
-    }
-    if controls[from] == to {
+    case controls(from) == to:
+        if wx.engine.classify(s[from], s[to]) == RelOveract {
+            return RelOveract
+        }
         return RelControl
-    }
-    if generates[to] == from {
+    case controls(to) == from && wx.engine.classify(s[to], s[from]) == RelInsult:
+        return RelInsult
+    case generates(to) == from:
         return RelWeaken
     }
     return RelNeutral
 }
 
+// SetPathologyRatios 设置相乘与相侮的触发比例，为 0 时不发生
+// overact 为克方与被克方的强度比，insult 为被克方与克方的强度比
+func (wx *WuXing) SetPathologyRatios(overact, insult float64) error {
+    wx.mu.Lock()
+    defer wx.mu.Unlock()
+
+    config := wx.engine.Config()
+    config.OveractRatio = overact
+    config.InsultRatio = insult
+    engine, err := NewWuXingEngine(config)
+    if err != nil {
+        return err
+    }
+    wx.engine = engine
+    return nil
+}
+
+// AddPathologyObserver 添加相乘、相侮的观察者
+func (wx *WuXing) AddPathologyObserver(observer PathologyObserver) {
+    wx.mu.Lock()
+    defer wx.mu.Unlock()
+    wx.pathologyObservers = append(wx.pathologyObservers, observer)
+}
+
 // AdjustElement 调整元素强度
 func (wx *WuXing) AdjustElement(phase Phase, delta int8) error {
     wx.mu.Lock()
@@ -196,15 +243,8 @@ func (wx *WuXing) AdjustElement(phase Phase, delta int8) error {
     element.mu.Lock()
     defer element.mu.Unlock()
 
-    newStrength := int16(element.strength) + int16(delta)
-    if newStrength < 0 {
-        newStrength = 0
-    } else if newStrength > 100 {
-        newStrength = 100
-    }
-
-    element.strength = uint8(newStrength)
-    element.lastUpdate = time.Now()
+    element.strength = math.Max(0, math.Min(100, element.strength+float64(delta)))
+    element.lastUpdate = clockOf(wx.ctx).Now()
 
     // 通知循环系统
     select {
@@ -225,10 +265,83 @@ func (wx *WuXing) GetElementStrength(phase Phase) (uint8, error) {
         return 0, ErrInvalidPhase
     }
 
+    element.mu.RLock()
+    defer element.mu.RUnlock()
+    return uint8(math.Round(element.strength)), nil
+}
+
+// ElementStrength 获取元素的连续强度
+func (wx *WuXing) ElementStrength(phase Phase) (float64, error) {
+    wx.mu.RLock()
+    defer wx.mu.RUnlock()
+
+    element, exists := wx.elements[phase]
+    if !exists {
+        return 0, ErrInvalidPhase
+    }
+
     element.mu.RLock()
     defer element.mu.RUnlock()
     return element.strength, nil
 }
+
+// Strengths 获取全部元素强度
+func (wx *WuXing) Strengths() Strengths {
+    wx.mu.RLock()
+    defer wx.mu.RUnlock()
+    return wx.strengths()
+}
+
+// strengths 读取全部元素强度，调用前须持有锁
+func (wx *WuXing) strengths() Strengths {
+    var s Strengths
+    for phase, element := range wx.elements {
+        element.mu.RLock()
+        s[phase] = element.strength
+        element.mu.RUnlock()
+    }
+    return s
+}
+
+// SetEngineConfig 设置五行引擎配置
+func (wx *WuXing) SetEngineConfig(config WuXingEngineConfig) error {
+    engine, err := NewWuXingEngine(config)
+    if err != nil {
+        return err
+    }
+
+    wx.mu.Lock()
+    defer wx.mu.Unlock()
+    wx.engine = engine
+    return nil
+}
+
+// RunCycle 执行一个五行作用周期，返回全部转移
+func (wx *WuXing) RunCycle() CycleReport {
+    wx.mu.Lock()
+    defer wx.mu.Unlock()
+
+    report := wx.engine.Cycle(wx.strengths())
+    now := clockOf(wx.ctx).Now()
+    for phase, element := range wx.elements {
+        element.mu.Lock()
+        element.strength = report.After[phase]
+        element.lastUpdate = now
+        element.mu.Unlock()
+    }
+
+    wx.cycleCount++
+    report.Cycle = wx.cycleCount
+    wx.lastReport = report
+    return report
+}
+
+// LastReport 获取最近一个周期的转移报告
+func (wx *WuXing) LastReport() CycleReport {
+    wx.mu.RLock()
+    defer wx.mu.RUnlock()
+    return wx.lastReport
+}
 func (wx *WuXing) ValidateRelationship(from, to Phase) error {
     wx.mu.RLock()
     defer wx.mu.RUnlock()
@@ -253,59 +366,21 @@ func (wx *WuXing) Close() error {
 }
 
 // processRelationships 处理五行关系
+// 相生、相克、相乘与相侮由引擎按当前强度处理，相乘与相侮的转移通知观察者
 func (wx *WuXing) processRelationships() {
-    wx.mu.Lock()
-    defer wx.mu.Unlock()
-
-    // 处理相生和相克关系
-    for phase, element := range wx.elements {
-        // 获取所有与当前元素相关的关系
-        for otherPhase, otherElement := range wx.elements {
-            if phase == otherPhase {
-                continue
-            }
-
-            relationship := wx.GetRelationship(phase, otherPhase)
-            wx.applyRelationship(element, otherElement, relationship)
-        }
+    report := wx.RunCycle()
+    pathologies := report.Pathologies()
+    if len(pathologies) == 0 {
+        return
     }
-}
 
-// applyRelationship 应用五行关系
-func (wx *WuXing) applyRelationship(from, to *Element, rel Relationship) {
-    from.mu.Lock()
-    to.mu.Lock()
-    defer from.mu.Unlock()
-    defer to.mu.Unlock()
+    wx.mu.RLock()
+    observers := append([]PathologyObserver(nil), wx.pathologyObservers...)
+    wx.mu.RUnlock()
 
-    switch rel {
-    case RelGenerate:
-        // 相生：增强目标元素
-        energyTransfer := from.strength / 10
-        if energyTransfer > 0 {
-            from.strength -= energyTransfer / 2
-            to.strength += energyTransfer
-        }
-    case RelControl:
-        // 相克：削弱目标元素
-        if from.strength > to.strength {
-            diff := (from.strength - to.strength) / 5
-            if diff > 0 {
-                to.strength -= diff
-            }
+    for _, transfer := range pathologies {
+        for _, observer := range observers {
+            observer.OnPathology(report.Cycle, transfer)
         }
-    case RelWeaken:
-        // 相泄：双方都减弱
-        if from.strength > 0 {
-            from.strength--
-        }
-        if to.strength > 0 {
-            to.strength--
-        }
-    }
-
-    // 确保范围有效
-    if to.strength > 100 {
-        to.strength = 100
     }
 }
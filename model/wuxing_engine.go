@@ -0,0 +1,220 @@
+// model/wuxing_engine.go
+
+package model
+
+import (
+    "errors"
+    "fmt"
+    "math"
+)
+
+// ErrInvalidEngineConfig 五行引擎配置不合法
+var ErrInvalidEngineConfig = errors.New("无效的五行引擎配置")
+
+// phaseCount 五行数量
+const phaseCount = 5
+
+// Strengths 五行强度，以 Phase 为下标
+type Strengths [phaseCount]float64
+
+// Total 强度总和
+func (s Strengths) Total() float64 {
+    total := 0.0
+    for _, v := range s {
+        total += v
+    }
+    return total
+}
+
+// generates 相生的子方
+func generates(p Phase) Phase {
+    return (p + 1) % phaseCount
+}
+
+// controls 相克的被克方
+func controls(p Phase) Phase {
+    return (p + 2) % phaseCount
+}
+
+// WuXingCoefficients 五行作用系数，均为每周期失去的能量占失去方强度的比例
+type WuXingCoefficients struct {
+    Generate float64 // 相生：母方流向子方
+    Control  float64 // 相克：被克方流向克方
+    Overact  float64 // 相乘：过强的克方对被克方的过度克制
+    Insult   float64 // 相侮：过强的被克方反克克方
+}
+
+// WuXingEngineConfig 五行引擎配置
+type WuXingEngineConfig struct {
+    Coefficients WuXingCoefficients
+    OveractRatio float64 // 克方与被克方强度之比不小于此值时为相乘，为 0 时不发生
+    InsultRatio  float64 // 被克方与克方强度之比不小于此值时为相侮，为 0 时不发生
+    Conserve     bool    // 能量守恒：每次转移失去与获得的能量相等，总量不变
+    Max          float64 // 强度上限，为 0 时不限
+}
+
+// DefaultWuXingEngineConfig 默认引擎配置
+func DefaultWuXingEngineConfig() WuXingEngineConfig {
+    return WuXingEngineConfig{
+        Coefficients: WuXingCoefficients{
+            Generate: 0.1,
+            Control:  0.05,
+            Overact:  0.15,
+            Insult:   0.05,
+        },
+        OveractRatio: 2,
+        InsultRatio:  1.5,
+        Conserve:     true,
+        Max:          100,
+    }
+}
+
+// Validate 校验配置
+func (c WuXingEngineConfig) Validate() error {
+    k := c.Coefficients
+    for name, v := range map[string]float64{
+        "generate": k.Generate,
+        "control":  k.Control,
+        "overact":  k.Overact,
+        "insult":   k.Insult,
+    } {
+        if v < 0 || v > 1 {
+            return fmt.Errorf("%w: %s 系数 %v 超出 [0,1]", ErrInvalidEngineConfig, name, v)
+        }
+    }
+    if c.OveractRatio < 0 || c.InsultRatio < 0 || c.Max < 0 {
+        return fmt.Errorf("%w: 比例与上限不能为负", ErrInvalidEngineConfig)
+    }
+    return nil
+}
+
+// Transfer 一次能量转移
+type Transfer struct {
+    From     Phase        // 失去能量的一方
+    To       Phase        // 获得能量的一方
+    Relation Relationship // 引起转移的关系
+    Amount   float64      // From 失去的能量
+    Received float64      // To 获得的能量，守恒时等于 Amount
+}
+
+// CycleReport 一个周期的转移报告
+type CycleReport struct {
+    Cycle     uint64
+    Before    Strengths
+    After     Strengths
+    Transfers []Transfer
+}
+
+// Pathologies 获取相乘与相侮引起的转移
+func (r CycleReport) Pathologies() []Transfer {
+    result := make([]Transfer, 0)
+    for _, t := range r.Transfers {
+        if t.Relation == RelOveract || t.Relation == RelInsult {
+            result = append(result, t)
+        }
+    }
+    return result
+}
+
+// Drift 周期前后总能量的变化，守恒时为 0
+func (r CycleReport) Drift() float64 {
+    return r.After.Total() - r.Before.Total()
+}
+
+// WuXingEngine 基于连续强度的五行引擎
+// 每周期先按相生次序执行相生，再执行相克、相乘或相侮；作用量按周期开始时的强度计算，
+// 依次应用并以当前强度为限，不会出现负强度
+//
+// 不守恒时相生的母方只失去作用量的一半，相克类作用的能量被耗散
+type WuXingEngine struct {
+    config WuXingEngineConfig
+}
+
+// NewWuXingEngine 创建五行引擎
+func NewWuXingEngine(config WuXingEngineConfig) (*WuXingEngine, error) {
+    if err := config.Validate(); err != nil {
+        return nil, err
+    }
+    return &WuXingEngine{config: config}, nil
+}
+
+// Config 获取引擎配置
+func (e *WuXingEngine) Config() WuXingEngineConfig {
+    return e.config
+}
+
+// Cycle 执行一个周期，返回转移报告
+func (e *WuXingEngine) Cycle(s Strengths) CycleReport {
+    report := CycleReport{
+        Before:    s,
+        Transfers: make([]Transfer, 0, 2*phaseCount),
+    }
+    start := s
+    k := e.config.Coefficients
+
+    // 相生：木->火->土->金->水->木
+    for p := Phase(0); p < phaseCount; p++ {
+        e.transfer(&s, &report, p, generates(p), RelGenerate, k.Generate*start[p])
+    }
+
+    // 相克：木->土->水->火->金->木，强弱悬殊时转为相乘或相侮
+    for p := Phase(0); p < phaseCount; p++ {
+        target := controls(p)
+        rel := e.classify(start[p], start[target])
+        switch rel {
+        case RelOveract:
+            e.transfer(&s, &report, target, p, rel, k.Overact*start[target])
+        case RelInsult:
+            e.transfer(&s, &report, p, target, rel, k.Insult*start[p])
+        default:
+            e.transfer(&s, &report, target, p, rel, k.Control*start[target])
+        }
+    }
+
+    report.After = s
+    return report
+}
+
+// classify 根据克方与被克方的强度判断相克、相乘或相侮
+func (e *WuXingEngine) classify(controller, target float64) Relationship {
+    if r := e.config.OveractRatio; r > 0 && controller > 0 && controller >= r*target {
+        return RelOveract
+    }
+    if r := e.config.InsultRatio; r > 0 && target > 0 && target >= r*controller {
+        return RelInsult
+    }
+    return RelControl
+}
+
+// transfer 从 from 向 to 转移能量，以 from 的当前强度与 to 的剩余容量为限
+func (e *WuXingEngine) transfer(s *Strengths, report *CycleReport, from, to Phase, rel Relationship, amount float64) {
+    headroom := math.Inf(1)
+    if e.config.Max > 0 {
+        headroom = math.Max(0, e.config.Max-s[to])
+    }
+
+    var lost, received float64
+    switch {
+    case e.config.Conserve:
+        lost = math.Min(math.Min(amount, s[from]), headroom)
+        received = lost
+    case rel == RelGenerate:
+        lost = math.Min(amount/2, s[from])
+        received = math.Min(amount, headroom)
+    default:
+        lost = math.Min(amount, s[from])
+    }
+    if lost <= 0 && received <= 0 {
+        return
+    }
+
+    s[from] -= lost
+    s[to] += received
+    report.Transfers = append(report.Transfers, Transfer{
+        From:     from,
+        To:       to,
+        Relation: rel,
+        Amount:   lost,
+        Received: received,
+    })
+}
@@ -0,0 +1,108 @@
+// model/wuxing_equilibrium.go
+
+package model
+
+import (
+    "math"
+    "sort"
+)
+
+// EquilibriumConfig 平衡分析配置
+type EquilibriumConfig struct {
+    Tolerance float64 // 偏离均值超过此值视为太过或不及
+    Epsilon   float64 // 一个周期内最大变化小于此值视为收敛
+    MaxCycles int     // 推演的最大周期数
+}
+
+// DefaultEquilibriumConfig 默认平衡分析配置
+func DefaultEquilibriumConfig() EquilibriumConfig {
+    return EquilibriumConfig{
+        Tolerance: 10,
+        Epsilon:   1e-6,
+        MaxCycles: 1000,
+    }
+}
+
+// EquilibriumReport 五行平衡分析结果
+type EquilibriumReport struct {
+    Strengths   Strengths      // 当前强度
+    Mean        float64        // 强度均值，即平衡时各元素的强度
+    Excessive   []Phase        // 太过的元素，按偏离程度降序
+    Deficient   []Phase        // 不及的元素，按偏离程度降序
+    SteadyState Strengths      // 反复执行五行循环后的稳态
+    Cycles      int            // 达到稳态所需的周期数
+    Converged   bool           // 是否在 MaxCycles 内收敛
+    Adjustments map[Phase]int8 // 恢复平衡建议的 AdjustElement 调整量
+}
+
+// Balanced 是否没有太过或不及的元素
+func (r EquilibriumReport) Balanced() bool {
+    return len(r.Excessive) == 0 && len(r.Deficient) == 0
+}
+
+// Analyze 以默认配置分析当前五行平衡
+func (wx *WuXing) Analyze() EquilibriumReport {
+    return wx.AnalyzeWith(DefaultEquilibriumConfig())
+}
+
+// AnalyzeWith 分析当前五行平衡
+func (wx *WuXing) AnalyzeWith(config EquilibriumConfig) EquilibriumReport {
+    return AnalyzeEquilibrium(wx.Strengths(), config)
+}
+
+// AnalyzeEquilibrium 分析给定强度的五行平衡，稳态按 processCycle 的相生循环推演
+func AnalyzeEquilibrium(s Strengths, config EquilibriumConfig) EquilibriumReport {
+    report := EquilibriumReport{
+        Strengths:   s,
+        Mean:        s.Total() / phaseCount,
+        Excessive:   make([]Phase, 0),
+        Deficient:   make([]Phase, 0),
+        Adjustments: make(map[Phase]int8),
+    }
+
+    // 太过与不及，按偏离程度降序
+    order := []Phase{PhaseWood, PhaseFire, PhaseEarth, PhaseMetal, PhaseWater}
+    sort.SliceStable(order, func(i, j int) bool {
+        return math.Abs(s[order[i]]-report.Mean) > math.Abs(s[order[j]]-report.Mean)
+    })
+    for _, p := range order {
+        deviation := s[p] - report.Mean
+        switch {
+        case deviation > config.Tolerance:
+            report.Excessive = append(report.Excessive, p)
+        case -deviation > config.Tolerance:
+            report.Deficient = append(report.Deficient, p)
+        }
+    }
+
+    // 调整到均值，总强度不变
+    for p := Phase(0); p < phaseCount; p++ {
+        if delta := math.Round(report.Mean - s[p]); delta != 0 {
+            report.Adjustments[p] = int8(math.Max(-100, math.Min(100, delta)))
+        }
+    }
+
+    // 推演稳态
+    current := s
+    for report.Cycles < config.MaxCycles {
+        next := promoteCycle(current)
+        report.Cycles++
+        if maxChange(current, next) < config.Epsilon {
+            current = next
+            report.Converged = true
+            break
+        }
+        current = next
+    }
+    report.SteadyState = current
+    return report
+}
+
+// maxChange 两组强度间的最大变化
+func maxChange(a, b Strengths) float64 {
+    change := 0.0
+    for i := range a {
+        change = math.Max(change, math.Abs(a[i]-b[i]))
+    }
+    return change
+}
@@ -0,0 +1,260 @@
+// model/wuxing_state.go
+
+package model
+
+import (
+    "errors"
+    "math"
+    "runtime"
+    "sync"
+    "time"
+
+    "github.com/Corphon/daoframe/core"
+    "github.com/Corphon/daoframe/core/clock"
+)
+
+// ErrSchedulerRunning 调度器已在运行
+var ErrSchedulerRunning = errors.New("五行调度器已在运行")
+
+// BalancedStrengths 五行强度均为 v
+func BalancedStrengths(v float64) Strengths {
+    return Strengths{v, v, v, v, v}
+}
+
+// WuXingState 实体级的轻量五行状态
+// 不持有协程与定时器，由 WuXingScheduler 批量推进
+type WuXingState struct {
+    mu        sync.RWMutex
+    strengths Strengths
+    cycles    uint64
+    lastCycle time.Time
+}
+
+// NewWuXingState 创建五行状态
+func NewWuXingState(initial Strengths) *WuXingState {
+    return &WuXingState{strengths: initial}
+}
+
+// Strengths 获取全部元素强度
+func (s *WuXingState) Strengths() Strengths {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+    return s.strengths
+}
+
+// Strength 获取元素强度
+func (s *WuXingState) Strength(phase Phase) (float64, error) {
+    if phase >= phaseCount {
+        return 0, ErrInvalidPhase
+    }
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+    return s.strengths[phase], nil
+}
+
+// Adjust 调整元素强度，结果限制在 0-100
+func (s *WuXingState) Adjust(phase Phase, delta float64) error {
+    if phase >= phaseCount {
+        return ErrInvalidPhase
+    }
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.strengths[phase] = math.Max(0, math.Min(100, s.strengths[phase]+delta))
+    return nil
+}
+
+// Cycles 获取已推进的周期数
+func (s *WuXingState) Cycles() uint64 {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+    return s.cycles
+}
+
+// LastCycle 获取最近一次推进的时间
+func (s *WuXingState) LastCycle() time.Time {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+    return s.lastCycle
+}
+
+// Analyze 分析五行平衡
+func (s *WuXingState) Analyze() EquilibriumReport {
+    return AnalyzeEquilibrium(s.Strengths(), DefaultEquilibriumConfig())
+}
+
+// advance 以引擎推进一个周期
+func (s *WuXingState) advance(engine *WuXingEngine, now time.Time) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.strengths = engine.Cycle(s.strengths).After
+    s.cycles++
+    s.lastCycle = now
+}
+
+// WuXingSchedulerConfig 五行调度器配置
+type WuXingSchedulerConfig struct {
+    Interval  time.Duration      // 推进间隔
+    BatchSize int                // 每批推进的状态数
+    Workers   int                // 并行推进的批数
+    Engine    WuXingEngineConfig // 全部状态共用的引擎配置
+}
+
+// DefaultWuXingSchedulerConfig 默认调度器配置
+func DefaultWuXingSchedulerConfig() WuXingSchedulerConfig {
+    return WuXingSchedulerConfig{
+        Interval:  time.Hour,
+        BatchSize: 256,
+        Workers:   runtime.GOMAXPROCS(0),
+        Engine:    DefaultWuXingEngineConfig(),
+    }
+}
+
+// WuXingScheduler 以一个定时器批量推进大量五行状态
+type WuXingScheduler struct {
+    mu        sync.RWMutex
+    ctx       *core.DaoContext
+    engine    *WuXingEngine
+    states    map[string]*WuXingState
+    interval  time.Duration
+    batchSize int
+    workers   int
+    running   bool
+    done      chan struct{}
+}
+
+// NewWuXingScheduler 创建五行调度器
+func NewWuXingScheduler(ctx *core.DaoContext, config WuXingSchedulerConfig) (*WuXingScheduler, error) {
+    engine, err := NewWuXingEngine(config.Engine)
+    if err != nil {
+        return nil, err
+    }
+
+    defaults := DefaultWuXingSchedulerConfig()
+    if config.Interval <= 0 {
+        config.Interval = defaults.Interval
+    }
+    if config.BatchSize <= 0 {
+        config.BatchSize = defaults.BatchSize
+    }
+    if config.Workers <= 0 {
+        config.Workers = defaults.Workers
+    }
+
+    return &WuXingScheduler{
+        ctx:       ctx,
+        engine:    engine,
+        states:    make(map[string]*WuXingState),
+        interval:  config.Interval,
+        batchSize: config.BatchSize,
+        workers:   config.Workers,
+    }, nil
+}
+
+// Register 登记状态，同名状态被替换
+func (ws *WuXingScheduler) Register(id string, s *WuXingState) {
+    ws.mu.Lock()
+    defer ws.mu.Unlock()
+    ws.states[id] = s
+}
+
+// Unregister 注销状态
+func (ws *WuXingScheduler) Unregister(id string) {
+    ws.mu.Lock()
+    defer ws.mu.Unlock()
+    delete(ws.states, id)
+}
+
+// State 获取状态
+func (ws *WuXingScheduler) State(id string) (*WuXingState, bool) {
+    ws.mu.RLock()
+    defer ws.mu.RUnlock()
+    s, exists := ws.states[id]
+    return s, exists
+}
+
+// Len 获取登记的状态数
+func (ws *WuXingScheduler) Len() int {
+    ws.mu.RLock()
+    defer ws.mu.RUnlock()
+    return len(ws.states)
+}
+
+// Advance 将全部状态推进一个周期，返回推进的状态数
+// 状态按 BatchSize 分批，最多 Workers 批并行
+func (ws *WuXingScheduler) Advance() int {
+    ws.mu.RLock()
+    states := make([]*WuXingState, 0, len(ws.states))
+    for _, s := range ws.states {
+        states = append(states, s)
+    }
+    engine := ws.engine
+    ws.mu.RUnlock()
+
+    now := clockOf(ws.ctx).Now()
+    batches := make(chan []*WuXingState)
+    var wg sync.WaitGroup
+    for i := 0; i < ws.workers; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for batch := range batches {
+                for _, s := range batch {
+                    s.advance(engine, now)
+                }
+            }
+        }()
+    }
+
+    for start := 0; start < len(states); start += ws.batchSize {
+        end := start + ws.batchSize
+        if end > len(states) {
+            end = len(states)
+        }
+        batches <- states[start:end]
+    }
+    close(batches)
+    wg.Wait()
+
+    return len(states)
+}
+
+// Start 按间隔推进全部状态
+func (ws *WuXingScheduler) Start() error {
+    ws.mu.Lock()
+    if ws.running {
+        ws.mu.Unlock()
+        return ErrSchedulerRunning
+    }
+    ws.running = true
+    ws.done = make(chan struct{})
+    done := ws.done
+    ws.mu.Unlock()
+
+    go ws.run(clockOf(ws.ctx), done)
+    return nil
+}
+
+// Stop 停止调度
+func (ws *WuXingScheduler) Stop() {
+    ws.mu.Lock()
+    defer ws.mu.Unlock()
+    if ws.running {
+        ws.running = false
+        close(ws.done)
+    }
+}
+
+// run 调度循环
+func (ws *WuXingScheduler) run(c clock.Clock, done chan struct{}) {
+    ticker := c.NewTicker(ws.interval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-done:
+            return
+        case <-ticker.C():
+            ws.Advance()
+        }
+    }
+}
@@ -3,16 +3,26 @@
 package model
 
 import (
+    "math"
     "sync"
     "time"
     "errors"
     
+    "github.com/Corphon/daoframe/core"
+    "github.com/Corphon/daoframe/core/clock"
     "github.com/Corphon/daoframe/core/state" 
 )
 
 var (
-    ErrImbalance = errors.New("阴阳失衡")
-    ErrExtreme   = errors.New("阴阳极端")
+    ErrImbalance   = errors.New("阴阳失衡")
+    ErrExtreme     = errors.New("阴阳极端")
+    ErrInvalidRate = errors.New("无效的变化速率")
+)
+
+// 默认的失衡与极端阈值，为阴阳差值
+const (
+    DefaultImbalanceThreshold = 10
+    DefaultExtremeThreshold   = 80
 )
 
 // Nature 定义事物的性质
@@ -38,21 +48,30 @@ type YinYang struct {
     yang    Polarity
     ctx     *core.DaoContext
     state   state.State
+    clock    clock.Clock    // 时钟
+    schedule clock.Schedule // 昼夜时刻表
     
     // 变化速率 (每秒)
     changeRate float64
+    strategy   BalanceStrategy
+    pending    float64 // 尚不足一个单位的累积变化
     
     // 新增平衡控制
     balanceCtrl struct {
-        threshold float64
+        threshold float64 // 失衡阈值
+        extreme   float64 // 极端阈值
         interval  time.Duration
         lastCheck time.Time
     }
     
     // 事件通知
-    observers []Observer
+    observers []subscription
+    nextID    uint64
     changes   chan Event
     done      chan struct{}
+
+    passive    bool // 不自动平衡，首次订阅时才启动事件投递协程
+    delivering bool // 投递协程已启动
 }
 
 type EventType uint8
@@ -63,86 +82,201 @@ const (
     EventExtreme
 )
 
+// Values 阴阳值
+type Values struct {
+    Yin  uint8
+    Yang uint8
+}
+
 // 新增事件系统
 type Event struct {
     Type      EventType
     Timestamp time.Time
+    Before    Values // 变化前的阴阳值
+    After     Values // 变化后的阴阳值
     Data      interface{}
 }
 
+// Observer 阴阳事件观察者
+type Observer interface {
+    OnEvent(event Event)
+}
+
+// ObserverFunc 以函数实现的观察者
+type ObserverFunc func(event Event)
+
+// OnEvent 调用函数
+func (f ObserverFunc) OnEvent(event Event) {
+    f(event)
+}
+
+// subscription 观察者订阅
+type subscription struct {
+    id       uint64
+    observer Observer
+    types    map[EventType]bool // 为 nil 时接收全部事件
+}
+
 // NewYinYang 创建新的阴阳实例
+// 时钟与昼夜时刻表取自 ctx，参见 clock.NewContext 与 clock.NewScheduleContext
 func NewYinYang(ctx *core.DaoContext) *YinYang {
+    return newYinYang(ctx, true)
+}
+
+// newYinYang 创建阴阳实例，autoBalance 为 false 时不启动自动平衡协程
+func newYinYang(ctx *core.DaoContext, autoBalance bool) *YinYang {
+    clk := clockOf(ctx)
     yy := &YinYang{
-        ctx: ctx,
+        ctx:      ctx,
+        clock:    clk,
+        schedule: scheduleOf(ctx),
         yin: Polarity{
             Value:    50,
             Nature:   NatureYin,
-            LastSync: time.Now(),
+            LastSync: clk.Now(),
         },
         yang: Polarity{
             Value:    50,
             Nature:   NatureYang,
-            LastSync: time.Now(),
+            LastSync: clk.Now(),
         },
         state:      state.StateActive,
         changeRate: 1.0,
-        changes:    make(chan struct{}, 1),
+        strategy:   DayNightStrategy(),
+        changes:    make(chan Event, 64),
         done:       make(chan struct{}),
     }
-
+    yy.balanceCtrl.threshold = DefaultImbalanceThreshold
+    yy.balanceCtrl.extreme = DefaultExtremeThreshold
+    yy.balanceCtrl.interval = time.Second
+    yy.balanceCtrl.lastCheck = clk.Now()
+
+    if !autoBalance {
+        yy.passive = true
+        return yy
+    }
+    yy.delivering = true
     go yy.autoBalance()
     return yy
 }
 
 // autoBalance 自动平衡协程
 func (yy *YinYang) autoBalance() {
-    ticker := time.NewTicker(time.Second)
+    ticker := yy.clock.NewTicker(yy.balanceCtrl.interval)
     defer ticker.Stop()
 
     for {
         select {
         case <-yy.done:
             return
-        case <-ticker.C:
+        case <-ticker.C():
             yy.balance()
-        case <-yy.changes:
-            yy.notifyChange()
+        case event := <-yy.changes:
+            yy.notifyChange(event)
         }
     }
 }
 
-// balance 执行阴阳平衡
+// deliver 事件投递协程，供不自动平衡的实例使用
+func (yy *YinYang) deliver() {
+    for {
+        select {
+        case <-yy.done:
+            return
+        case event := <-yy.changes:
+            yy.notifyChange(event)
+        }
+    }
+}
+
+// balance 按策略执行阴阳平衡，变化量不超过 changeRate 与经过时长之积
 func (yy *YinYang) balance() {
     yy.mu.Lock()
     defer yy.mu.Unlock()
 
-    now := time.Now()
-    hour := now.Hour()
+    now := yy.clock.Now()
+    elapsed := now.Sub(yy.balanceCtrl.lastCheck)
+    yy.balanceCtrl.lastCheck = now
+    if elapsed <= 0 {
+        return
+    }
 
-    // 昼夜自然变化
-    if hour >= 6 && hour < 18 {
-        // 白天：阳升阴降
-        yy.adjustPolarity(&yy.yang, &yy.yin)
-    } else {
-        // 夜晚：阴升阳降
-        yy.adjustPolarity(&yy.yin, &yy.yang)
+    st := BalanceState{
+        Yin:      yy.yin.Value,
+        Yang:     yy.yang.Value,
+        Now:      now,
+        Elapsed:  elapsed,
+        Rate:     yy.changeRate,
+        Schedule: yy.schedule,
     }
+    limit := st.MaxDelta()
+    delta := math.Max(-limit, math.Min(limit, yy.strategy.Adjust(st)))
+    yy.adjustPolarity(delta, now)
 }
 
-// adjustPolarity 调整阴阳极性
-func (yy *YinYang) adjustPolarity(rise, fall *Polarity) {
-    // 基于变化速率计算调整量
-    delta := yy.changeRate
+// adjustPolarity 调整阴阳极性，delta 为正时阴升阳降
+// 不足一个单位的变化累积到下次调整
+func (yy *YinYang) adjustPolarity(delta float64, now time.Time) {
+    before := yy.values()
 
-    if rise.Value < 100 {
-        rise.Value++
+    yy.pending += delta
+    units := math.Trunc(yy.pending)
+    yy.pending -= units
+
+    if units != 0 {
+        yy.yin.Value = clampPolarity(float64(yy.yin.Value) + units)
+        yy.yang.Value = clampPolarity(float64(yy.yang.Value) - units)
     }
-    if fall.Value > 0 {
-        fall.Value--
+
+    yy.yin.LastSync = now
+    yy.yang.LastSync = now
+    yy.emit(before, now)
+}
+
+// clampPolarity 将极性值限制在 0-100
+func clampPolarity(v float64) uint8 {
+    return uint8(math.Max(0, math.Min(100, v)))
+}
+
+// SetStrategy 设置平衡策略，为 nil 时使用昼夜策略
+func (yy *YinYang) SetStrategy(strategy BalanceStrategy) {
+    if strategy == nil {
+        strategy = DayNightStrategy()
+    }
+    yy.mu.Lock()
+    defer yy.mu.Unlock()
+    yy.strategy = strategy
+    yy.pending = 0
+}
+
+// SetChangeRate 设置每秒的最大变化量
+func (yy *YinYang) SetChangeRate(rate float64) error {
+    if rate < 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+        return ErrInvalidRate
     }
+    yy.mu.Lock()
+    defer yy.mu.Unlock()
+    yy.changeRate = rate
+    return nil
+}
+
+// ChangeRate 获取每秒的最大变化量
+func (yy *YinYang) ChangeRate() float64 {
+    yy.mu.RLock()
+    defer yy.mu.RUnlock()
+    return yy.changeRate
+}
 
-    rise.LastSync = time.Now()
-    fall.LastSync = time.Now()
+// SetThresholds 设置失衡与极端阈值，均为阴阳差值
+func (yy *YinYang) SetThresholds(imbalance, extreme float64) error {
+    if imbalance < 0 || extreme < imbalance || extreme > 100 {
+        return ErrImbalance
+    }
+    yy.mu.Lock()
+    defer yy.mu.Unlock()
+    yy.balanceCtrl.threshold = imbalance
+    yy.balanceCtrl.extreme = extreme
+    return nil
 }
 
 // GetRatio 获取阴阳比例
@@ -169,14 +303,12 @@ func (yy *YinYang) Adjust(yinDelta, yangDelta int8) error {
         return ErrExtreme
     }
 
+    before := yy.values()
     yy.yin.Value = uint8(newYin)
     yy.yang.Value = uint8(newYang)
     
     // 通知变化
-    select {
-    case yy.changes <- struct{}{}:
-    default:
-    }
+    yy.emit(before, yy.clock.Now())
 
     return nil
 }
@@ -187,7 +319,9 @@ func (yy *YinYang) Transform() {
     defer yy.mu.Unlock()
 
     // 交换阴阳值
+    before := yy.values()
     yy.yin.Value, yy.yang.Value = yy.yang.Value, yy.yin.Value
+    yy.emit(before, yy.clock.Now())
 }
 
 // Split 阴阳分离，用于"二生三"
@@ -210,6 +344,37 @@ func (yy *YinYang) Split() (*YinYang, *YinYang) {
     return yinInstance, yangInstance
 }
 
+// derive 为分化树分化出偏向 line 的新实例，阴阳值由当前值向 line 偏移 bias
+// 新实例不启动自动平衡协程，由分化树统一调节
+func (yy *YinYang) derive(line Line, bias uint8) *YinYang {
+    yy.mu.RLock()
+    yin, yang := float64(yy.yin.Value), float64(yy.yang.Value)
+    ctx := yy.ctx
+    yy.mu.RUnlock()
+
+    if ctx != nil {
+        ctx = ctx.Clone()
+    }
+    shift := float64(bias)
+    if line == LineYang {
+        shift = -shift
+    }
+
+    child := newYinYang(ctx, false)
+    child.mu.Lock()
+    child.yin.Value = clampPolarity(yin + shift)
+    child.yang.Value = clampPolarity(yang - shift)
+    child.mu.Unlock()
+    return child
+}
+
+// nudge 以浮点量调整阴阳，正值阴升阳降
+func (yy *YinYang) nudge(delta float64) {
+    yy.mu.Lock()
+    defer yy.mu.Unlock()
+    yy.adjustPolarity(delta, yy.clock.Now())
+}
+
 // Close 关闭并清理资源
 func (yy *YinYang) Close() error {
     close(yy.done)
@@ -221,11 +386,7 @@ func (yy *YinYang) IsBalanced() bool {
     yy.mu.RLock()
     defer yy.mu.RUnlock()
 
-    diff := int(yy.yin.Value) - int(yy.yang.Value)
-    if diff < 0 {
-        diff = -diff
-    }
-    return diff <= 10
+    return yy.classify(yy.values()) == EventBalance
 }
 
 // GetDominant 获取主导属性
@@ -241,7 +402,85 @@ func (yy *YinYang) GetDominant() Nature {
     return NatureTai
 }
 
-// notifyChange 通知变化
-func (yy *YinYang) notifyChange() {
-    // 这里可以添加观察者模式，通知其他组件阴阳变化
+// Subscribe 订阅阴阳事件，types 为空时接收全部事件，返回取消订阅的函数
+// 事件在阴阳状态于平衡、失衡、极端之间转变时发出，由后台协程依次投递；分化树的节点在首次订阅时才启动投递协程
+func (yy *YinYang) Subscribe(observer Observer, types ...EventType) func() {
+    sub := subscription{observer: observer}
+    if len(types) > 0 {
+        sub.types = make(map[EventType]bool, len(types))
+        for _, t := range types {
+            sub.types[t] = true
+        }
+    }
+
+    yy.mu.Lock()
+    yy.nextID++
+    sub.id = yy.nextID
+    yy.observers = append(yy.observers, sub)
+    if yy.passive && !yy.delivering {
+        yy.delivering = true
+        go yy.deliver()
+    }
+    yy.mu.Unlock()
+
+    return func() {
+        yy.mu.Lock()
+        defer yy.mu.Unlock()
+        for i, s := range yy.observers {
+            if s.id == sub.id {
+                yy.observers = append(yy.observers[:i], yy.observers[i+1:]...)
+                return
+            }
+        }
+    }
+}
+
+// values 当前阴阳值，调用前须持有锁
+func (yy *YinYang) values() Values {
+    return Values{Yin: yy.yin.Value, Yang: yy.yang.Value}
+}
+
+// classify 判断阴阳状态，调用前须持有锁
+func (yy *YinYang) classify(v Values) EventType {
+    diff := math.Abs(float64(v.Yin) - float64(v.Yang))
+    switch {
+    case diff >= yy.balanceCtrl.extreme:
+        return EventExtreme
+    case diff > yy.balanceCtrl.threshold:
+        return EventImbalance
+    default:
+        return EventBalance
+    }
+}
+
+// emit 状态转变时发出事件，队列已满时丢弃，调用前须持有锁
+func (yy *YinYang) emit(before Values, now time.Time) {
+    after := yy.values()
+    eventType := yy.classify(after)
+    if eventType == yy.classify(before) {
+        return
+    }
+
+    select {
+    case yy.changes <- Event{
+        Type:      eventType,
+        Timestamp: now,
+        Before:    before,
+        After:     after,
+    }:
+    default:
+    }
+}
+
+// notifyChange 通知观察者
+func (yy *YinYang) notifyChange(event Event) {
+    yy.mu.RLock()
+    observers := append([]subscription(nil), yy.observers...)
+    yy.mu.RUnlock()
+
+    for _, sub := range observers {
+        if sub.types == nil || sub.types[event.Type] {
+            sub.observer.OnEvent(event)
+        }
+    }
 }
@@ -0,0 +1,101 @@
+// model/yin_yang_strategy.go
+
+package model
+
+import (
+    "math"
+    "sync"
+    "time"
+
+    "github.com/Corphon/daoframe/core/clock"
+)
+
+// BalanceState 平衡策略的输入
+type BalanceState struct {
+    Yin      uint8
+    Yang     uint8
+    Now      time.Time
+    Elapsed  time.Duration  // 距上次平衡的时长
+    Rate     float64        // 每秒允许的最大变化量
+    Schedule clock.Schedule // 昼夜时刻表
+}
+
+// MaxDelta 本次平衡允许的最大变化量
+func (s BalanceState) MaxDelta() float64 {
+    return s.Rate * s.Elapsed.Seconds()
+}
+
+// BalanceStrategy 阴阳平衡策略
+// Adjust 返回阴的期望变化量，正值阴升阳降；YinYang 按 MaxDelta 限幅并累积不足一个单位的部分
+type BalanceStrategy interface {
+    Adjust(state BalanceState) float64
+}
+
+// BalanceStrategyFunc 以函数实现的自定义策略
+type BalanceStrategyFunc func(state BalanceState) float64
+
+// Adjust 调用函数
+func (f BalanceStrategyFunc) Adjust(state BalanceState) float64 {
+    return f(state)
+}
+
+// DayNightStrategy 昼夜策略：白天阳升阴降，夜晚阴升阳降，均以最大速率变化
+func DayNightStrategy() BalanceStrategy {
+    return BalanceStrategyFunc(func(state BalanceState) float64 {
+        if state.Schedule.IsDay(state.Now) {
+            return -state.MaxDelta()
+        }
+        return state.MaxDelta()
+    })
+}
+
+// PIStrategy 比例积分策略，使阴的比例趋向目标
+type PIStrategy struct {
+    mu       sync.Mutex
+    target   float64 // 阴的目标比例 0-1
+    kp       float64
+    ki       float64
+    integral float64
+}
+
+// NewPIStrategy 创建比例积分策略，target 为阴的目标比例
+func NewPIStrategy(target, kp, ki float64) *PIStrategy {
+    return &PIStrategy{
+        target: math.Max(0, math.Min(1, target)),
+        kp:     kp,
+        ki:     ki,
+    }
+}
+
+// SetTarget 设置阴的目标比例
+func (p *PIStrategy) SetTarget(target float64) {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    p.target = math.Max(0, math.Min(1, target))
+}
+
+// Reset 清零积分项
+func (p *PIStrategy) Reset() {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    p.integral = 0
+}
+
+// Adjust 按阴的偏差计算变化量，输出受限时积分不再累积
+func (p *PIStrategy) Adjust(state BalanceState) float64 {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    total := float64(state.Yin) + float64(state.Yang)
+    err := p.target*total - float64(state.Yin)
+    dt := state.Elapsed.Seconds()
+
+    integral := p.integral + err*dt
+    out := p.kp*err + p.ki*integral
+    if limit := state.MaxDelta(); math.Abs(out) <= limit {
+        p.integral = integral
+    } else {
+        out = math.Copysign(limit, out)
+    }
+    return out
+}
@@ -0,0 +1,204 @@
+// model/yin_yang_tree.go
+
+package model
+
+import (
+    "errors"
+)
+
+// ErrNotDerived 节点尚未分化到所需层级
+var ErrNotDerived = errors.New("阴阳尚未分化")
+
+// Line 爻
+type Line uint8
+
+const (
+    LineYin  Line = iota // 阴爻 ⚋
+    LineYang             // 阳爻 ⚊
+)
+
+// Image 四象，以自下而上的两爻表示
+type Image uint8
+
+const (
+    ImageGreaterYang Image = iota // 太阳 ⚌
+    ImageLesserYin                // 少阴 ⚍
+    ImageLesserYang               // 少阳 ⚎
+    ImageGreaterYin               // 太阴 ⚏
+)
+
+// imageLines 四象的爻，自下而上
+var imageLines = map[Image][2]Line{
+    ImageGreaterYang: {LineYang, LineYang},
+    ImageLesserYin:   {LineYang, LineYin},
+    ImageLesserYang:  {LineYin, LineYang},
+    ImageGreaterYin:  {LineYin, LineYin},
+}
+
+// Lines 获取四象的爻，自下而上
+func (i Image) Lines() [2]Line {
+    return imageLines[i]
+}
+
+// 树的分化层级
+const (
+    DepthTaiJi   = 0 // 太极
+    DepthPoles   = 1 // 两仪
+    DepthImages  = 2 // 四象
+    DepthTrigram = 3 // 八卦
+)
+
+// DeriveConfig 阴阳分化配置
+type DeriveConfig struct {
+    Bias        uint8   // 子节点相对父节点向所取之爻偏移的量，默认 20
+    Propagation float64 // 子节点变化传递给父节点的比例 0-1
+}
+
+// DefaultDeriveConfig 默认分化配置
+func DefaultDeriveConfig() DeriveConfig {
+    return DeriveConfig{
+        Bias:        20,
+        Propagation: 0.5,
+    }
+}
+
+// YinYangNode 阴阳分化树的节点
+// 太极生两仪，两仪生四象，四象生八卦；每个节点的阴阳由父节点向所取之爻偏移而来，
+// 通过 Adjust 调整节点时变化按 Propagation 逐级传回父节点
+type YinYangNode struct {
+    yy       *YinYang
+    parent   *YinYangNode
+    line     Line
+    depth    int
+    children [2]*YinYangNode // 下标为所取之爻
+    config   DeriveConfig
+}
+
+// DeriveTrigrams 从 root 分化出四象与八卦，返回树的根节点
+// 分化出的节点不启动自动平衡协程，只经由 Adjust 与子节点的传递变化
+func DeriveTrigrams(root *YinYang, config DeriveConfig) *YinYangNode {
+    node := &YinYangNode{
+        yy:     root,
+        depth:  DepthTaiJi,
+        config: config,
+    }
+    node.derive(DepthTrigram)
+    return node
+}
+
+// derive 递归分化到指定层级
+func (n *YinYangNode) derive(depth int) {
+    if n.depth >= depth {
+        return
+    }
+    for _, line := range []Line{LineYin, LineYang} {
+        child := &YinYangNode{
+            yy:     n.yy.derive(line, n.config.Bias),
+            parent: n,
+            line:   line,
+            depth:  n.depth + 1,
+            config: n.config,
+        }
+        child.yy.SetChangeRate(0)
+        child.derive(depth)
+        n.children[line] = child
+    }
+}
+
+// YinYang 获取节点的阴阳
+func (n *YinYangNode) YinYang() *YinYang {
+    return n.yy
+}
+
+// Parent 获取父节点，根节点返回 nil
+func (n *YinYangNode) Parent() *YinYangNode {
+    return n.parent
+}
+
+// Depth 获取节点层级
+func (n *YinYangNode) Depth() int {
+    return n.depth
+}
+
+// Child 获取取 line 分化出的子节点
+func (n *YinYangNode) Child(line Line) *YinYangNode {
+    return n.children[line]
+}
+
+// Lines 获取自根节点到此节点所取的爻，自下而上
+func (n *YinYangNode) Lines() []Line {
+    lines := make([]Line, n.depth)
+    for node := n; node.parent != nil; node = node.parent {
+        lines[node.depth-1] = node.line
+    }
+    return lines
+}
+
+// Node 沿给定的爻自下而上查找节点
+func (n *YinYangNode) Node(lines ...Line) (*YinYangNode, error) {
+    node := n
+    for _, line := range lines {
+        if node.children[line] == nil {
+            return nil, ErrNotDerived
+        }
+        node = node.children[line]
+    }
+    return node, nil
+}
+
+// ImageNode 获取四象节点
+func (n *YinYangNode) ImageNode(image Image) (*YinYangNode, error) {
+    lines := image.Lines()
+    return n.Node(lines[:]...)
+}
+
+// TrigramNode 获取八卦节点
+func (n *YinYangNode) TrigramNode(t Trigram) (*YinYangNode, error) {
+    lines := t.Lines()
+    return n.Node(lines[:]...)
+}
+
+// Energy 节点的能量：自两仪至此节点，各节点阴阳与所取之爻一致的比例的均值
+// 根节点返回 0.5
+func (n *YinYangNode) Energy() float64 {
+    if n.parent == nil {
+        return 0.5
+    }
+
+    total := 0.0
+    for node := n; node.parent != nil; node = node.parent {
+        yin, yang := node.yy.GetRatio()
+        if node.line == LineYang {
+            total += yang
+        } else {
+            total += yin
+        }
+    }
+    return total / float64(n.depth)
+}
+
+// Adjust 调整节点阴阳，并将阴阳差的变化按比例逐级传回父节点
+func (n *YinYangNode) Adjust(yinDelta, yangDelta int8) error {
+    if err := n.yy.Adjust(yinDelta, yangDelta); err != nil {
+        return err
+    }
+
+    shift := (float64(yinDelta) - float64(yangDelta)) / 2
+    for node := n.parent; node != nil; node = node.parent {
+        shift *= n.config.Propagation
+        node.yy.nudge(shift)
+    }
+    return nil
+}
+
+// Close 关闭分化出的全部节点，根节点的阴阳由调用方关闭
+func (n *YinYangNode) Close() error {
+    for _, child := range n.children {
+        if child == nil {
+            continue
+        }
+        child.Close()
+        child.yy.Close()
+    }
+    return nil
+}
@@ -0,0 +1,111 @@
+//storage/journal.go
+package storage
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/url"
+
+    "github.com/Corphon/daoframe/core/state"
+)
+
+// JournalBackend 基于 Store 的状态日志后端
+// 键格式为 prefix + 转义后的 subject + "/" + 20位序号，保证按键排序即按序号排序
+// subject 经 url.PathEscape 转义，含 "/" 的主体不会与其他主体的前缀冲突
+type JournalBackend struct {
+    store  Store
+    prefix string
+}
+
+// NewJournalBackend 创建状态日志后端
+func NewJournalBackend(store Store, prefix string) *JournalBackend {
+    return &JournalBackend{
+        store:  store,
+        prefix: prefix,
+    }
+}
+
+// Append 追加日志条目
+func (b *JournalBackend) Append(ctx context.Context, entry state.JournalEntry) error {
+    value, err := json.Marshal(entry)
+    if err != nil {
+        return err
+    }
+    return b.store.Set(ctx, b.key(entry.Subject, entry.Sequence), value, &Options{Versioned: true})
+}
+
+// AppendBatch 在一个事务中追加一批日志条目
+func (b *JournalBackend) AppendBatch(ctx context.Context, entries []state.JournalEntry) error {
+    tx, err := b.store.Begin(ctx)
+    if err != nil {
+        return err
+    }
+    for _, entry := range entries {
+        value, err := json.Marshal(entry)
+        if err != nil {
+            tx.Rollback()
+            return err
+        }
+        if err := tx.Set(b.key(entry.Subject, entry.Sequence), value, &Options{Versioned: true}); err != nil {
+            tx.Rollback()
+            return err
+        }
+    }
+    return tx.Commit()
+}
+
+// Load 加载序号大于 after 的日志条目
+func (b *JournalBackend) Load(ctx context.Context, subject string, after uint64) ([]state.JournalEntry, error) {
+    items, err := b.store.List(ctx, &Filter{
+        Prefix:  b.subjectPrefix(subject),
+        OrderBy: "key",
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    entries := make([]state.JournalEntry, 0, len(items))
+    for _, item := range items {
+        var entry state.JournalEntry
+        if err := json.Unmarshal(item.Value, &entry); err != nil {
+            return nil, fmt.Errorf("decode journal entry %s: %w", item.Key, err)
+        }
+        if entry.Sequence > after {
+            entries = append(entries, entry)
+        }
+    }
+    return entries, nil
+}
+
+// LastSequence 获取最后的日志序号
+func (b *JournalBackend) LastSequence(ctx context.Context, subject string) (uint64, error) {
+    items, err := b.store.List(ctx, &Filter{
+        Prefix:    b.subjectPrefix(subject),
+        OrderBy:   "key",
+        OrderDesc: true,
+        Limit:     1,
+    })
+    if err != nil {
+        return 0, err
+    }
+    if len(items) == 0 {
+        return 0, nil
+    }
+
+    var entry state.JournalEntry
+    if err := json.Unmarshal(items[0].Value, &entry); err != nil {
+        return 0, fmt.Errorf("decode journal entry %s: %w", items[0].Key, err)
+    }
+    return entry.Sequence, nil
+}
+
+// subjectPrefix 主体键前缀
+func (b *JournalBackend) subjectPrefix(subject string) string {
+    return b.prefix + url.PathEscape(subject) + "/"
+}
+
+// key 条目键
+func (b *JournalBackend) key(subject string, seq uint64) string {
+    return fmt.Sprintf("%s%020d", b.subjectPrefix(subject), seq)
+}
@@ -1,10 +1,19 @@
 package system
 
+import (
+    "sync"
+
+    "github.com/Corphon/daoframe/model"
+)
+
 // EvolutionSystem 演化系统
 type EvolutionSystem struct {
+    mu        sync.RWMutex
     universe  *Universe
     patterns  []EvolutionPattern
     cycles    map[CyclePhase]*CycleState
+    hexagram  model.Hexagram            // 当前卦
+    lastShift *model.HexagramTransition // 最近一次卦变
 }
 
 // EvolutionPattern 演化模式
@@ -20,9 +29,48 @@ func (es *EvolutionSystem) ProcessEvolution() error {
     // 1. 获取当前周期
     currentCycle := es.universe.timeSystem.GetCurrentCycle()
     
-    // 2. 应用演化模式
+    // 2. 应用演化模式，在锁内复制以免与卦变更新竞争
+    es.mu.RLock()
     pattern := es.patterns[currentCycle.Phase]
+    es.mu.RUnlock()
     
     // 3. 处理状态转换
     return es.applyPattern(pattern)
 }
+
+// WatchHexagram 订阅卦象的卦变，返回取消订阅的函数
+func (es *EvolutionSystem) WatchHexagram(state *model.HexagramState) func() {
+    es.mu.Lock()
+    es.hexagram = state.Current()
+    es.mu.Unlock()
+    return state.Subscribe(es)
+}
+
+// OnHexagramTransition 响应卦变：记录之卦，并以其外卦作为各演化模式的卦
+func (es *EvolutionSystem) OnHexagramTransition(transition model.HexagramTransition) {
+    es.mu.Lock()
+    defer es.mu.Unlock()
+
+    es.hexagram = transition.To
+    es.lastShift = &transition
+    for i := range es.patterns {
+        es.patterns[i].trigram = transition.To.Upper()
+    }
+}
+
+// Hexagram 获取当前卦
+func (es *EvolutionSystem) Hexagram() model.Hexagram {
+    es.mu.RLock()
+    defer es.mu.RUnlock()
+    return es.hexagram
+}
+
+// LastTransition 获取最近一次卦变，尚无卦变时返回 false
+func (es *EvolutionSystem) LastTransition() (model.HexagramTransition, bool) {
+    es.mu.RLock()
+    defer es.mu.RUnlock()
+    if es.lastShift == nil {
+        return model.HexagramTransition{}, false
+    }
+    return *es.lastShift, true
+}